@@ -10,7 +10,10 @@ import (
 	appHttp "agones-minecraft/services/http"
 	"agones-minecraft/services/k8s"
 	"agones-minecraft/services/k8s/agones"
+	"agones-minecraft/services/mods"
+	"agones-minecraft/services/storage"
 	"agones-minecraft/services/validator"
+	"agones-minecraft/services/workers"
 )
 
 func Run() error {
@@ -20,14 +23,22 @@ func Run() error {
 	log.Init()
 	// Initializes k8s cluster config
 	k8s.InitConfig()
+	// Initializes k8s clientset for pod commands
+	k8s.InitClient()
 	// Initializes app http client
 	appHttp.Init()
+	// Registers Modrinth and SpigotMC mod fetchers
+	mods.Init()
+	// Initializes world backup storage
+	storage.Init()
 	// Connects to k8s cluster and initializes agones client and informer
 	agones.Init()
 	// Initializes session and oauth session redis store
 	sessions.Init()
 	// Initializes database connections and migrates (in development)
 	db.Init()
+	// Starts background workers
+	workers.Start()
 	// Initializes Twitch ODIC provider for login with Twitch
 	twitch.Init()
 	// Initializes custom validators
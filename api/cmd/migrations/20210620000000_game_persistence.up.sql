@@ -0,0 +1 @@
+ALTER TABLE games ADD COLUMN IF NOT EXISTS persistent boolean NOT NULL DEFAULT false;
@@ -0,0 +1,7 @@
+ALTER TABLE games
+  DROP COLUMN IF EXISTS difficulty,
+  DROP COLUMN IF EXISTS game_mode,
+  DROP COLUMN IF EXISTS version,
+  DROP COLUMN IF EXISTS seed,
+  DROP COLUMN IF EXISTS level_type,
+  DROP COLUMN IF EXISTS whitelist;
@@ -0,0 +1,7 @@
+ALTER TABLE games
+  ADD COLUMN IF NOT EXISTS difficulty varchar(25) NOT NULL DEFAULT 'easy',
+  ADD COLUMN IF NOT EXISTS game_mode varchar(25) NOT NULL DEFAULT 'survival',
+  ADD COLUMN IF NOT EXISTS version varchar(25) NOT NULL DEFAULT 'LATEST',
+  ADD COLUMN IF NOT EXISTS seed varchar(64),
+  ADD COLUMN IF NOT EXISTS level_type varchar(25) NOT NULL DEFAULT 'default',
+  ADD COLUMN IF NOT EXISTS whitelist boolean NOT NULL DEFAULT false;
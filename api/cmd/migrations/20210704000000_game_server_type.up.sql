@@ -0,0 +1 @@
+ALTER TABLE games ADD COLUMN IF NOT EXISTS type varchar(25) NOT NULL DEFAULT 'vanilla';
@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS game_mods (
+  id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+  game_id uuid NOT NULL REFERENCES games (id) ON DELETE CASCADE,
+  source varchar(25) NOT NULL,
+  project_id varchar(100) NOT NULL,
+  name varchar(100) NOT NULL,
+  version varchar(100) NOT NULL,
+  kind varchar(25) NOT NULL,
+  url text NOT NULL,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  updated_at timestamptz NOT NULL DEFAULT now(),
+  deleted_at timestamptz
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS game_mods_game_id_source_project_id_key ON game_mods (game_id, source, project_id) WHERE deleted_at IS NULL;
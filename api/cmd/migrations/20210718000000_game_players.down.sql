@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS game_whitelist CASCADE;
+
+--gopg:split
+
+DROP TABLE IF EXISTS game_ops CASCADE;
@@ -0,0 +1,25 @@
+CREATE TABLE IF NOT EXISTS game_whitelist (
+  id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+  game_id uuid NOT NULL REFERENCES games (id) ON DELETE CASCADE,
+  mc_id uuid NOT NULL,
+  username varchar(16) NOT NULL,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  updated_at timestamptz NOT NULL DEFAULT now(),
+  deleted_at timestamptz
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS game_whitelist_game_id_mc_id_key ON game_whitelist (game_id, mc_id) WHERE deleted_at IS NULL;
+
+--gopg:split
+
+CREATE TABLE IF NOT EXISTS game_ops (
+  id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+  game_id uuid NOT NULL REFERENCES games (id) ON DELETE CASCADE,
+  mc_id uuid NOT NULL,
+  username varchar(16) NOT NULL,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  updated_at timestamptz NOT NULL DEFAULT now(),
+  deleted_at timestamptz
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS game_ops_game_id_mc_id_key ON game_ops (game_id, mc_id) WHERE deleted_at IS NULL;
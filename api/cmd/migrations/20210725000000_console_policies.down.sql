@@ -0,0 +1,5 @@
+ALTER TABLE users DROP COLUMN IF EXISTS console_deny;
+
+--gopg:split
+
+ALTER TABLE users DROP COLUMN IF EXISTS console_allow;
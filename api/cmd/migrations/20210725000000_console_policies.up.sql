@@ -0,0 +1,5 @@
+ALTER TABLE users ADD COLUMN IF NOT EXISTS console_allow text[];
+
+--gopg:split
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS console_deny text[];
@@ -0,0 +1,6 @@
+ALTER TABLE games
+  DROP COLUMN IF EXISTS backup_enabled,
+  DROP COLUMN IF EXISTS backup_cron,
+  DROP COLUMN IF EXISTS backup_keep_last,
+  DROP COLUMN IF EXISTS backup_keep_daily,
+  DROP COLUMN IF EXISTS backup_keep_weekly;
@@ -0,0 +1,6 @@
+ALTER TABLE games
+  ADD COLUMN IF NOT EXISTS backup_enabled boolean NOT NULL DEFAULT true,
+  ADD COLUMN IF NOT EXISTS backup_cron varchar(100) NOT NULL DEFAULT '0 */6 * * *',
+  ADD COLUMN IF NOT EXISTS backup_keep_last integer NOT NULL DEFAULT 10,
+  ADD COLUMN IF NOT EXISTS backup_keep_daily integer NOT NULL DEFAULT 7,
+  ADD COLUMN IF NOT EXISTS backup_keep_weekly integer NOT NULL DEFAULT 4;
@@ -0,0 +1,2 @@
+ALTER TABLE games
+  ADD COLUMN IF NOT EXISTS idle_timeout integer NOT NULL DEFAULT 0;
@@ -0,0 +1,14 @@
+CREATE TABLE IF NOT EXISTS game_schedules (
+  id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+  game_id uuid NOT NULL REFERENCES games (id) ON DELETE CASCADE,
+  start_cron varchar(100) NOT NULL,
+  stop_cron varchar(100) NOT NULL,
+  timezone varchar(64) NOT NULL DEFAULT 'UTC',
+  enabled boolean NOT NULL DEFAULT true,
+  checked_at timestamptz NOT NULL DEFAULT now(),
+  created_at timestamptz NOT NULL DEFAULT now(),
+  updated_at timestamptz NOT NULL DEFAULT now(),
+  deleted_at timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS game_schedules_game_id_idx ON game_schedules (game_id) WHERE deleted_at IS NULL;
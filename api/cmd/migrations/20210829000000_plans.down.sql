@@ -0,0 +1,5 @@
+ALTER TABLE users DROP COLUMN IF EXISTS plan_id;
+
+--gopg:split
+
+DROP TABLE IF EXISTS plans CASCADE;
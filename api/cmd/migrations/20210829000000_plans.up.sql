@@ -0,0 +1,22 @@
+CREATE TABLE IF NOT EXISTS plans (
+  id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+  name varchar(25) NOT NULL UNIQUE,
+  max_games integer NOT NULL DEFAULT 0,
+  max_running_games integer NOT NULL DEFAULT 0,
+  max_slots integer NOT NULL DEFAULT 0,
+  max_memory integer NOT NULL DEFAULT 0,
+  max_backup_storage integer NOT NULL DEFAULT 0,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  updated_at timestamptz NOT NULL DEFAULT now(),
+  deleted_at timestamptz
+);
+
+--gopg:split
+
+INSERT INTO plans (name, max_games, max_running_games, max_slots, max_memory, max_backup_storage)
+VALUES ('free', 1, 1, 10, 2048, 5120)
+ON CONFLICT (name) DO NOTHING;
+
+--gopg:split
+
+ALTER TABLE users ADD COLUMN IF NOT EXISTS plan_id uuid REFERENCES plans (id) ON DELETE SET NULL;
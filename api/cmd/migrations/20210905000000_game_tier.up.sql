@@ -0,0 +1,2 @@
+ALTER TABLE games
+  ADD COLUMN IF NOT EXISTS tier varchar(25) NOT NULL DEFAULT 'small';
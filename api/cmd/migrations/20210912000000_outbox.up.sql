@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS outbox (
+  id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+  type varchar(25) NOT NULL,
+  game_id uuid NOT NULL,
+  resource_name varchar(100) NOT NULL,
+  game_server jsonb,
+  persistent boolean NOT NULL DEFAULT false,
+  attempts integer NOT NULL DEFAULT 0,
+  last_error text,
+  available_at timestamptz NOT NULL DEFAULT now(),
+  processed_at timestamptz,
+  created_at timestamptz NOT NULL DEFAULT now()
+);
+
+CREATE INDEX IF NOT EXISTS outbox_pending_idx ON outbox (available_at) WHERE processed_at IS NULL;
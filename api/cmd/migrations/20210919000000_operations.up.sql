@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS operations (
+  id uuid PRIMARY KEY DEFAULT uuid_generate_v4(),
+  game_id uuid NOT NULL REFERENCES games (id) ON DELETE CASCADE,
+  user_id uuid NOT NULL REFERENCES users (id) ON DELETE CASCADE,
+  type varchar(10) NOT NULL,
+  status varchar(20) NOT NULL DEFAULT 'queued',
+  reason text,
+  created_at timestamptz NOT NULL DEFAULT now(),
+  updated_at timestamptz NOT NULL DEFAULT now(),
+  finished_at timestamptz
+);
+
+CREATE INDEX IF NOT EXISTS operations_game_id_idx ON operations (game_id) WHERE finished_at IS NULL;
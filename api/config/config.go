@@ -1,9 +1,13 @@
 package config
 
 import (
+	"encoding/json"
+	"fmt"
 	"log"
+	"time"
 
 	"github.com/spf13/viper"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 // env variables
@@ -25,6 +29,20 @@ const (
 	JWT_SECRET           = "JWT_SECRET"
 	DNS_ZONE             = "DNS_ZONE"
 	BUCKET_NAME          = "BUCKET_NAME"
+	WORLD_STORAGE_CLASS  = "WORLD_STORAGE_CLASS"
+	WORLD_STORAGE_SIZE   = "WORLD_STORAGE_SIZE"
+	CONSOLE_ALLOW        = "CONSOLE_ALLOW"
+	CONSOLE_DENY         = "CONSOLE_DENY"
+	STORAGE_DRIVER       = "STORAGE_DRIVER"
+	STORAGE_DIRECTORY    = "STORAGE_DIRECTORY"
+	GCS_CREDENTIALS_FILE = "GCS_CREDENTIALS_FILE"
+	RETENTION_INTERVAL   = "RETENTION_INTERVAL"
+	CONSOLE_ORIGINS      = "CONSOLE_ORIGINS"
+	WORLD_UPLOAD_LIMIT   = "WORLD_UPLOAD_LIMIT"
+	IDLE_CHECK_INTERVAL  = "IDLE_CHECK_INTERVAL"
+	DEFAULT_PLAN         = "DEFAULT_PLAN"
+	SERVER_TIERS         = "SERVER_TIERS"
+	RECONCILE_INTERVAL   = "RECONCILE_INTERVAL"
 )
 
 const (
@@ -40,11 +58,35 @@ func InitConfig() {
 	viper.SetDefault(ENV, Development) // default to development
 	viper.SetDefault(PORT, "8080")     // default to 8080
 
+	viper.SetDefault(WORLD_STORAGE_SIZE, "10Gi") // default to 10Gi persistent world volumes
+
+	viper.SetDefault(STORAGE_DIRECTORY, "storage") // default to ./storage for local storage
+
+	viper.SetDefault(RETENTION_INTERVAL, "1h") // default to pruning backups and outbox messages hourly
+
+	viper.SetDefault(IDLE_CHECK_INTERVAL, "1m") // default to checking for idle games every minute
+
+	viper.SetDefault(RECONCILE_INTERVAL, "5m") // default to reconciling every game every 5 minutes
+
+	viper.SetDefault(WORLD_UPLOAD_LIMIT, 512) // default to 512MB world uploads
+
+	viper.SetDefault(DEFAULT_PLAN, "free") // default to the plan created by migrations
+
+	viper.SetDefault(CONSOLE_ALLOW, defaultConsoleAllow) // default to gameplay and moderation commands
+
+	viper.SetDefault(CONSOLE_DENY, "stop") // games are stopped through the API so their worlds are backed up
+
+	viper.SetDefault(SERVER_TIERS, defaultServerTiers) // default to small, medium and large tiers
+
 	if err := viper.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
 			log.Fatal(err)
 		}
 	}
+
+	if _, err := parseServerTiers(); err != nil {
+		log.Fatal(err)
+	}
 }
 
 type environment string
@@ -146,3 +188,147 @@ func GetDNSZone() string {
 func GetBucketName() string {
 	return viper.GetString(BUCKET_NAME)
 }
+
+// StorageClass and size for persistent world volumes
+type WorldStorageConfig struct {
+	StorageClass string
+	Size         string
+}
+
+// Returns persistent world volume configuration. An empty StorageClass uses the cluster's default StorageClass
+func GetWorldStorageConfig() *WorldStorageConfig {
+	return &WorldStorageConfig{
+		StorageClass: viper.GetString(WORLD_STORAGE_CLASS),
+		Size:         viper.GetString(WORLD_STORAGE_SIZE),
+	}
+}
+
+// Object storage for world backups
+type StorageConfig struct {
+	// gcs or local
+	Driver          string
+	Bucket          string
+	CredentialsFile string
+	LocalDirectory  string
+}
+
+// Returns storage configuration. The driver defaults to local storage in development and GCS in production
+func GetStorageConfig() *StorageConfig {
+	driver := viper.GetString(STORAGE_DRIVER)
+	if driver == "" {
+		driver = "gcs"
+		if GetEnv() == Development {
+			driver = "local"
+		}
+	}
+
+	return &StorageConfig{
+		Driver:          driver,
+		Bucket:          GetBucketName(),
+		CredentialsFile: viper.GetString(GCS_CREDENTIALS_FILE),
+		LocalDirectory:  viper.GetString(STORAGE_DIRECTORY),
+	}
+}
+
+// Returns the interval expired world backups are deleted on
+func GetBackupRetentionInterval() time.Duration {
+	return viper.GetDuration(RETENTION_INTERVAL)
+}
+
+// Returns the interval games are checked for players on. Games are stopped on the first check after their idle timeout
+func GetIdleCheckInterval() time.Duration {
+	return viper.GetDuration(IDLE_CHECK_INTERVAL)
+}
+
+// Returns the interval every game is reconciled on in addition to reconciling GameServer changes
+func GetReconcileInterval() time.Duration {
+	return viper.GetDuration(RECONCILE_INTERVAL)
+}
+
+// Console commands allowed for users without their own allowed commands. Empty allows all commands not denied
+func GetConsoleAllow() []string {
+	return viper.GetStringSlice(CONSOLE_ALLOW)
+}
+
+// Console commands denied for all users
+func GetConsoleDeny() []string {
+	return viper.GetStringSlice(CONSOLE_DENY)
+}
+
+// Origins allowed to open console WebSockets. Empty only allows same origin requests
+func GetConsoleOrigins() []string {
+	return viper.GetStringSlice(CONSOLE_ORIGINS)
+}
+
+// Returns the maximum size of uploaded world archives in bytes
+func GetWorldUploadLimit() int64 {
+	return viper.GetInt64(WORLD_UPLOAD_LIMIT) << 20
+}
+
+// Returns the name of the plan users without a plan are on
+func GetDefaultPlan() string {
+	return viper.GetString(DEFAULT_PLAN)
+}
+
+// Name of the tier games are created with when none is selected
+const DefaultServerTier = "small"
+
+// Default CONSOLE_ALLOW commands. Commands that stop the server, turn off saving or run datapack functions are left out
+const defaultConsoleAllow = "list say tell msg w me tellraw title time weather difficulty gamemode defaultgamemode gamerule " +
+	"give clear effect enchant xp experience tp teleport kill summon setblock fill clone spawnpoint setworldspawn " +
+	"scoreboard team kick ban ban-ip pardon pardon-ip banlist whitelist op deop seed execute"
+
+// Default tiers as SERVER_TIERS JSON
+const defaultServerTiers = `{
+	"small": {"cpu": "500m", "cpuLimit": "1", "memory": 1536, "heap": 1024},
+	"medium": {"cpu": "1", "cpuLimit": "2", "memory": 3072, "heap": 2048},
+	"large": {"cpu": "2", "cpuLimit": "4", "memory": 6144, "heap": 4608}
+}`
+
+// Compute resources of a game's server container
+type ServerTier struct {
+	// CPU request and limit as Kubernetes quantities. e.g. 500m
+	CPU      string `json:"cpu"`
+	CPULimit string `json:"cpuLimit"`
+	// Memory request and limit in MiB
+	Memory int `json:"memory"`
+	// Java heap size in MiB. Kept below Memory to leave room for memory outside of the heap
+	Heap int `json:"heap"`
+}
+
+// Returns server tiers by name
+func GetServerTiers() map[string]*ServerTier {
+	tiers, _ := parseServerTiers()
+	return tiers
+}
+
+// Returns a server tier by name
+func GetServerTier(name string) (*ServerTier, bool) {
+	tier, ok := GetServerTiers()[name]
+	return tier, ok
+}
+
+func parseServerTiers() (map[string]*ServerTier, error) {
+	tiers := map[string]*ServerTier{}
+	if err := json.Unmarshal([]byte(viper.GetString(SERVER_TIERS)), &tiers); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", SERVER_TIERS, err)
+	}
+
+	if _, ok := tiers[DefaultServerTier]; !ok {
+		return nil, fmt.Errorf("invalid %s: missing %s tier", SERVER_TIERS, DefaultServerTier)
+	}
+
+	for name, tier := range tiers {
+		if tier == nil || tier.Memory <= 0 || tier.Heap <= 0 || tier.Heap > tier.Memory {
+			return nil, fmt.Errorf("invalid %s: %s tier needs a heap no larger than its memory", SERVER_TIERS, name)
+		}
+		if _, err := resource.ParseQuantity(tier.CPU); err != nil {
+			return nil, fmt.Errorf("invalid %s: %s tier cpu: %w", SERVER_TIERS, name, err)
+		}
+		if _, err := resource.ParseQuantity(tier.CPULimit); err != nil {
+			return nil, fmt.Errorf("invalid %s: %s tier cpuLimit: %w", SERVER_TIERS, name, err)
+		}
+	}
+
+	return tiers, nil
+}
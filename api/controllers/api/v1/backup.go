@@ -0,0 +1,105 @@
+package v1Controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	v1Err "agones-minecraft/errors/v1"
+	"agones-minecraft/middleware/session"
+	apiErr "agones-minecraft/resources/api/v1/errors"
+	gamev1Resource "agones-minecraft/resources/api/v1/game"
+	gamev1Service "agones-minecraft/services/api/v1/game"
+)
+
+func ListBackups(c *gin.Context) {
+	v, _ := c.Get(session.SessionUserIDKey)
+	userId := v.(uuid.UUID)
+
+	name := c.Param("name")
+
+	backups := []*gamev1Resource.Backup{}
+
+	if err := gamev1Service.ListBackups(&backups, userId, name); err != nil {
+		if err == gamev1Service.ErrGameServerNotFound {
+			c.Error(apiErr.NewNotFoundError(err, v1Err.ErrGameNotFound))
+		} else {
+			c.Error(apiErr.NewInternalServerError(err, v1Err.ErrListingBackups))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, backups)
+}
+
+func CreateBackup(c *gin.Context) {
+	v, _ := c.Get(session.SessionUserIDKey)
+	userId := v.(uuid.UUID)
+
+	name := c.Param("name")
+
+	var backup gamev1Resource.Backup
+
+	if err := gamev1Service.CreateBackup(&backup, userId, name); err != nil {
+		if err == gamev1Service.ErrGameServerNotFound {
+			c.Error(apiErr.NewNotFoundError(err, v1Err.ErrGameNotFound))
+		} else if err == gamev1Service.ErrGameNotOnline {
+			c.Error(apiErr.NewBadRequestError(err, v1Err.ErrGameNotOnline))
+		} else if _, ok := err.(*gamev1Service.ErrBackingUpGame); ok {
+			c.Error(apiErr.NewInternalServerError(err, v1Err.ErrBackingUpGame))
+		} else {
+			c.Error(apiErr.NewInternalServerError(err, v1Err.ErrCreatingBackup))
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, backup)
+}
+
+func GetBackupDownload(c *gin.Context) {
+	v, _ := c.Get(session.SessionUserIDKey)
+	userId := v.(uuid.UUID)
+
+	name := c.Param("name")
+	backupName := c.Param("backup")
+
+	var download gamev1Resource.BackupDownload
+
+	if err := gamev1Service.GetBackupDownload(&download, userId, name, backupName); err != nil {
+		if err == gamev1Service.ErrGameServerNotFound {
+			c.Error(apiErr.NewNotFoundError(err, v1Err.ErrGameNotFound))
+		} else if err == gamev1Service.ErrBackupNotFound {
+			c.Error(apiErr.NewNotFoundError(err, v1Err.ErrBackupNotFound))
+		} else {
+			c.Error(apiErr.NewInternalServerError(err, v1Err.ErrGettingBackupDownload))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, download)
+}
+
+// Restores a backup the next time the game is started
+func RestoreBackup(c *gin.Context) {
+	v, _ := c.Get(session.SessionUserIDKey)
+	userId := v.(uuid.UUID)
+
+	name := c.Param("name")
+	backupName := c.Param("backup")
+
+	var game gamev1Resource.Game
+
+	if err := gamev1Service.RestoreBackup(&game, userId, name, backupName); err != nil {
+		if err == gamev1Service.ErrGameServerNotFound {
+			c.Error(apiErr.NewNotFoundError(err, v1Err.ErrGameNotFound))
+		} else if err == gamev1Service.ErrBackupNotFound {
+			c.Error(apiErr.NewNotFoundError(err, v1Err.ErrBackupNotFound))
+		} else {
+			c.Error(apiErr.NewInternalServerError(err, v1Err.ErrRestoringBackup))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, game)
+}
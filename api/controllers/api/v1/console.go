@@ -0,0 +1,191 @@
+package v1Controllers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
+	"go.uber.org/zap"
+
+	"agones-minecraft/config"
+	v1Err "agones-minecraft/errors/v1"
+	"agones-minecraft/middleware/session"
+	apiErr "agones-minecraft/resources/api/v1/errors"
+	gamev1Resource "agones-minecraft/resources/api/v1/game"
+	gamev1Service "agones-minecraft/services/api/v1/game"
+	"agones-minecraft/services/rcon"
+)
+
+const (
+	// Time allowed to write a message to the console client
+	consoleWriteWait time.Duration = time.Second * 10
+	// Time allowed to read the next pong from the console client
+	consolePongWait time.Duration = time.Second * 60
+	// Pings are sent before the pong wait is exceeded
+	consolePingPeriod time.Duration = consolePongWait * 9 / 10
+)
+
+var consoleUpgrader = websocket.Upgrader{
+	CheckOrigin: checkConsoleOrigin,
+}
+
+// Proxies a WebSocket to an RCON session on the game. Each text message is run as a command
+// and answered with its result
+func Console(c *gin.Context) {
+	v, _ := c.Get(session.SessionUserIDKey)
+	userId := v.(uuid.UUID)
+
+	name := c.Param("name")
+
+	console, err := gamev1Service.OpenConsole(userId, name)
+	if err != nil {
+		handleConsoleError(c, err, v1Err.ErrOpeningConsole)
+		return
+	}
+	defer console.Close()
+
+	conn, err := consoleUpgrader.Upgrade(c.Writer, c.Request, nil)
+	if err != nil {
+		// upgrader has already responded with an error
+		return
+	}
+	defer conn.Close()
+
+	conn.SetReadLimit(int64(rcon.MaxCommandLength))
+	conn.SetReadDeadline(time.Now().Add(consolePongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(consolePongWait))
+	})
+
+	done := make(chan struct{})
+	defer close(done)
+
+	go func() {
+		ticker := time.NewTicker(consolePingPeriod)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(consoleWriteWait)); err != nil {
+					return
+				}
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	for {
+		_, msg, err := conn.ReadMessage()
+		if err != nil {
+			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseNormalClosure) {
+				zap.L().Warn("error reading from console", zap.String("game", name), zap.Error(err))
+			}
+			return
+		}
+
+		command := strings.TrimSpace(string(msg))
+		if command == "" {
+			continue
+		}
+
+		result := gamev1Resource.CommandResult{Command: command}
+
+		output, cmdErr := console.Command(command)
+		if cmdErr != nil {
+			result.Error = cmdErr.Error()
+		} else {
+			result.Output = output
+		}
+
+		conn.SetWriteDeadline(time.Now().Add(consoleWriteWait))
+		if err := conn.WriteJSON(result); err != nil {
+			return
+		}
+
+		// any other error means the RCON session is no longer usable
+		if cmdErr != nil && cmdErr != gamev1Service.ErrCommandNotAllowed && cmdErr != rcon.ErrCommandTooLong {
+			conn.WriteControl(
+				websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseInternalServerErr, "console disconnected"),
+				time.Now().Add(consoleWriteWait),
+			)
+			return
+		}
+	}
+}
+
+// Runs a single console command on the game
+func RunCommand(c *gin.Context) {
+	v, _ := c.Get(session.SessionUserIDKey)
+	userId := v.(uuid.UUID)
+
+	name := c.Param("name")
+
+	var body gamev1Resource.CommandBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		var verrs validator.ValidationErrors
+		if errors.As(err, &verrs) {
+			c.Errors = append(c.Errors, apiErr.NewValidationError(verrs, v1Err.ErrRunCommandValidation)...)
+		} else if err == io.EOF {
+			c.Error(apiErr.NewBadRequestError(ErrMissingRequestBody, v1Err.ErrMissingRequestBody))
+		} else {
+			c.Error(apiErr.NewInternalServerError(err, v1Err.ErrMalformedJSON))
+		}
+		return
+	}
+
+	var output string
+
+	if err := gamev1Service.RunCommand(&output, userId, name, body.Command); err != nil {
+		handleConsoleError(c, err, v1Err.ErrRunningCommand)
+		return
+	}
+
+	c.JSON(http.StatusOK, gamev1Resource.CommandResult{
+		Command: body.Command,
+		Output:  output,
+	})
+}
+
+func handleConsoleError(c *gin.Context, err error, id v1Err.ErrorID) {
+	if err == gamev1Service.ErrGameServerNotFound {
+		c.Error(apiErr.NewNotFoundError(err, v1Err.ErrGameNotFound))
+	} else if err == gamev1Service.ErrConsoleUnsupported {
+		c.Error(apiErr.NewBadRequestError(err, v1Err.ErrConsoleUnsupported))
+	} else if err == gamev1Service.ErrGameNotOnline {
+		c.Error(apiErr.NewBadRequestError(err, v1Err.ErrGameNotOnline))
+	} else if err == gamev1Service.ErrCommandNotAllowed {
+		c.Error(apiErr.NewForbiddenError(err, v1Err.ErrCommandNotAllowed))
+	} else {
+		c.Error(apiErr.NewInternalServerError(err, id))
+	}
+}
+
+// Allows same origin requests and requests from configured console origins
+func checkConsoleOrigin(r *http.Request) bool {
+	origin := r.Header.Get("Origin")
+	if origin == "" {
+		return true
+	}
+
+	for _, allowed := range config.GetConsoleOrigins() {
+		if origin == allowed {
+			return true
+		}
+	}
+
+	u, err := url.Parse(origin)
+	if err != nil {
+		return false
+	}
+
+	return strings.EqualFold(u.Host, r.Host)
+}
@@ -0,0 +1,46 @@
+package v1Controllers
+
+import (
+	"io"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"agones-minecraft/middleware/session"
+	gamev1Service "agones-minecraft/services/api/v1/game"
+)
+
+// Interval of keep-alive comments that stop proxies from closing idle event streams
+const eventsKeepAliveInterval time.Duration = time.Second * 30
+
+// Streams changes of the user's games as Server-Sent Events
+func GameEvents(c *gin.Context) {
+	v, _ := c.Get(session.SessionUserIDKey)
+	userId := v.(uuid.UUID)
+
+	events, unsubscribe := gamev1Service.SubscribeGameEvents(userId)
+	defer unsubscribe()
+
+	ticker := time.NewTicker(eventsKeepAliveInterval)
+	defer ticker.Stop()
+
+	c.Header("Cache-Control", "no-cache")
+	c.Header("X-Accel-Buffering", "no")
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case event, ok := <-events:
+			if !ok {
+				return false
+			}
+			c.SSEvent(string(event.Type), event)
+			return true
+		case <-ticker.C:
+			_, err := io.WriteString(w, ": keep-alive\n\n")
+			return err == nil
+		case <-c.Request.Context().Done():
+			return false
+		}
+	})
+}
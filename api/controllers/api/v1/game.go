@@ -80,13 +80,17 @@ func CreateJava(c *gin.Context) {
 			c.Error(apiErr.NewBadRequestError(err, v1Err.ErrSubdomainTaken))
 		} else if err == gamev1Service.ErrGameServerNameTaken {
 			c.Error(apiErr.NewBadRequestError(err, v1Err.ErrGameServerNameTaken))
+		} else if _, ok := err.(*gamev1Service.ErrInvalidServerType); ok {
+			c.Error(apiErr.NewBadRequestError(err, v1Err.ErrInvalidServerType))
+		} else if _, ok := err.(*gamev1Service.ErrQuotaExceeded); ok {
+			c.Error(apiErr.NewForbiddenError(err, v1Err.ErrQuotaExceeded))
 		} else {
 			c.Error(apiErr.NewInternalServerError(err, v1Err.ErrCreatingGame))
 		}
 		return
 	}
 
-	c.JSON(http.StatusCreated, game)
+	c.JSON(http.StatusAccepted, game)
 }
 
 func CreateBedrock(c *gin.Context) {
@@ -108,18 +112,57 @@ func CreateBedrock(c *gin.Context) {
 
 	var game gamev1Resource.Game
 
-	if err := gamev1Service.CreateGame(&game, gamev1Model.JavaEdition, body, userId); err != nil {
+	if err := gamev1Service.CreateGame(&game, gamev1Model.BedrockEdition, body, userId); err != nil {
 		if err == gamev1Service.ErrSubdomainTaken {
 			c.Error(apiErr.NewBadRequestError(err, v1Err.ErrSubdomainTaken))
 		} else if err == gamev1Service.ErrGameServerNameTaken {
 			c.Error(apiErr.NewBadRequestError(err, v1Err.ErrGameServerNameTaken))
+		} else if _, ok := err.(*gamev1Service.ErrInvalidServerType); ok {
+			c.Error(apiErr.NewBadRequestError(err, v1Err.ErrInvalidServerType))
+		} else if _, ok := err.(*gamev1Service.ErrQuotaExceeded); ok {
+			c.Error(apiErr.NewForbiddenError(err, v1Err.ErrQuotaExceeded))
 		} else {
 			c.Error(apiErr.NewInternalServerError(err, v1Err.ErrCreatingGame))
 		}
 		return
 	}
 
-	c.JSON(http.StatusCreated, game)
+	c.JSON(http.StatusAccepted, game)
+}
+
+func UpdateGame(c *gin.Context) {
+	v, _ := c.Get(session.SessionUserIDKey)
+	userId := v.(uuid.UUID)
+
+	name := c.Param("name")
+
+	var body gamev1Resource.GameSettingsBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		var verrs validator.ValidationErrors
+		if errors.As(err, &verrs) {
+			c.Errors = append(c.Errors, apiErr.NewValidationError(verrs, v1Err.ErrUpdateGameValidation)...)
+		} else if err == io.EOF {
+			c.Error(apiErr.NewBadRequestError(ErrMissingRequestBody, v1Err.ErrMissingRequestBody))
+		} else {
+			c.Error(apiErr.NewInternalServerError(err, v1Err.ErrMalformedJSON))
+		}
+		return
+	}
+
+	var game gamev1Resource.Game
+
+	if err := gamev1Service.UpdateGameSettings(&game, userId, name, body); err != nil {
+		if err == gamev1Service.ErrGameServerNotFound {
+			c.Error(apiErr.NewNotFoundError(err, v1Err.ErrGameNotFound))
+		} else if err == gamev1Service.ErrGameNotStopped {
+			c.Error(apiErr.NewBadRequestError(err, v1Err.ErrGameNotStopped))
+		} else {
+			c.Error(apiErr.NewInternalServerError(err, v1Err.ErrUpdatingGame))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, game)
 }
 
 func DeleteGame(c *gin.Context) {
@@ -128,7 +171,9 @@ func DeleteGame(c *gin.Context) {
 
 	name := c.Param("name")
 
-	if err := gamev1Service.DeleteGame(userId, name); err != nil {
+	var operation gamev1Resource.Operation
+
+	if err := gamev1Service.DeleteGame(&operation, userId, name); err != nil {
 		if err == gamev1Service.ErrGameServerNotFound {
 			c.Error(apiErr.NewNotFoundError(err, v1Err.ErrGameNotFound))
 		} else {
@@ -142,5 +187,51 @@ func DeleteGame(c *gin.Context) {
 		return
 	}
 
-	c.Status(http.StatusNoContent)
+	c.JSON(http.StatusAccepted, operation)
+}
+
+func StopGame(c *gin.Context) {
+	v, _ := c.Get(session.SessionUserIDKey)
+	userId := v.(uuid.UUID)
+
+	name := c.Param("name")
+
+	var game gamev1Resource.Game
+
+	if err := gamev1Service.StopGame(&game, userId, name); err != nil {
+		if err == gamev1Service.ErrGameServerNotFound {
+			c.Error(apiErr.NewNotFoundError(err, v1Err.ErrGameNotFound))
+		} else if err == gamev1Service.ErrGameAlreadyStopped {
+			c.Error(apiErr.NewBadRequestError(err, v1Err.ErrGameAlreadyStopped))
+		} else {
+			c.Error(apiErr.NewInternalServerError(err, v1Err.ErrStoppingGame))
+		}
+		return
+	}
+
+	c.JSON(http.StatusAccepted, game)
+}
+
+func StartGame(c *gin.Context) {
+	v, _ := c.Get(session.SessionUserIDKey)
+	userId := v.(uuid.UUID)
+
+	name := c.Param("name")
+
+	var game gamev1Resource.Game
+
+	if err := gamev1Service.StartGame(&game, userId, name); err != nil {
+		if err == gamev1Service.ErrGameServerNotFound {
+			c.Error(apiErr.NewNotFoundError(err, v1Err.ErrGameNotFound))
+		} else if err == gamev1Service.ErrGameAlreadyStarted {
+			c.Error(apiErr.NewBadRequestError(err, v1Err.ErrGameAlreadyStarted))
+		} else if _, ok := err.(*gamev1Service.ErrQuotaExceeded); ok {
+			c.Error(apiErr.NewForbiddenError(err, v1Err.ErrQuotaExceeded))
+		} else {
+			c.Error(apiErr.NewInternalServerError(err, v1Err.ErrStartingGame))
+		}
+		return
+	}
+
+	c.JSON(http.StatusAccepted, game)
 }
@@ -0,0 +1,110 @@
+package v1Controllers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+
+	v1Err "agones-minecraft/errors/v1"
+	"agones-minecraft/middleware/session"
+	apiErr "agones-minecraft/resources/api/v1/errors"
+	gamev1Resource "agones-minecraft/resources/api/v1/game"
+	gamev1Service "agones-minecraft/services/api/v1/game"
+	"agones-minecraft/services/mods"
+)
+
+var (
+	ErrInvalidModID error = errors.New("invalid mod id")
+)
+
+func ListGameMods(c *gin.Context) {
+	v, _ := c.Get(session.SessionUserIDKey)
+	userId := v.(uuid.UUID)
+
+	name := c.Param("name")
+
+	gameMods := []*gamev1Resource.GameMod{}
+
+	if err := gamev1Service.ListGameMods(&gameMods, userId, name); err != nil {
+		if err == gamev1Service.ErrGameServerNotFound {
+			c.Error(apiErr.NewNotFoundError(err, v1Err.ErrGameNotFound))
+		} else {
+			c.Error(apiErr.NewInternalServerError(err, v1Err.ErrListingGameMods))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gameMods)
+}
+
+func AddGameMod(c *gin.Context) {
+	v, _ := c.Get(session.SessionUserIDKey)
+	userId := v.(uuid.UUID)
+
+	name := c.Param("name")
+
+	var body gamev1Resource.AddModBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		var verrs validator.ValidationErrors
+		if errors.As(err, &verrs) {
+			c.Errors = append(c.Errors, apiErr.NewValidationError(verrs, v1Err.ErrAddGameModValidation)...)
+		} else if err == io.EOF {
+			c.Error(apiErr.NewBadRequestError(ErrMissingRequestBody, v1Err.ErrMissingRequestBody))
+		} else {
+			c.Error(apiErr.NewInternalServerError(err, v1Err.ErrMalformedJSON))
+		}
+		return
+	}
+
+	var gameMod gamev1Resource.GameMod
+
+	if err := gamev1Service.AddGameMod(&gameMod, userId, name, body); err != nil {
+		if err == gamev1Service.ErrGameServerNotFound {
+			c.Error(apiErr.NewNotFoundError(err, v1Err.ErrGameNotFound))
+		} else if err == gamev1Service.ErrModAlreadyAdded {
+			c.Error(apiErr.NewBadRequestError(err, v1Err.ErrModAlreadyAdded))
+		} else if _, ok := err.(*gamev1Service.ErrResolvingMod); ok {
+			var incompatible *mods.ErrIncompatibleServerType
+			if errors.Is(err, mods.ErrModNotFound) || errors.Is(err, mods.ErrNoCompatibleFile) || errors.Is(err, mods.ErrUnpinnedVersion) || errors.As(err, &incompatible) {
+				c.Error(apiErr.NewBadRequestError(errors.Unwrap(err), v1Err.ErrResolvingMod))
+			} else {
+				c.Error(apiErr.NewInternalServerError(err, v1Err.ErrResolvingMod))
+			}
+		} else {
+			c.Error(apiErr.NewInternalServerError(err, v1Err.ErrAddingGameMod))
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, gameMod)
+}
+
+func RemoveGameMod(c *gin.Context) {
+	v, _ := c.Get(session.SessionUserIDKey)
+	userId := v.(uuid.UUID)
+
+	name := c.Param("name")
+
+	modId, err := uuid.Parse(c.Param("modId"))
+	if err != nil {
+		c.Error(apiErr.NewBadRequestError(ErrInvalidModID, v1Err.ErrInvalidModID))
+		return
+	}
+
+	if err := gamev1Service.RemoveGameMod(userId, name, modId); err != nil {
+		if err == gamev1Service.ErrGameServerNotFound {
+			c.Error(apiErr.NewNotFoundError(err, v1Err.ErrGameNotFound))
+		} else if err == gamev1Service.ErrGameModNotFound {
+			c.Error(apiErr.NewNotFoundError(err, v1Err.ErrGameModNotFound))
+		} else {
+			c.Error(apiErr.NewInternalServerError(err, v1Err.ErrRemovingGameMod))
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
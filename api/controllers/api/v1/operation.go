@@ -0,0 +1,39 @@
+package v1Controllers
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	v1Err "agones-minecraft/errors/v1"
+	"agones-minecraft/middleware/session"
+	apiErr "agones-minecraft/resources/api/v1/errors"
+	gamev1Resource "agones-minecraft/resources/api/v1/game"
+	gamev1Service "agones-minecraft/services/api/v1/game"
+)
+
+// Gets the progress of a create, start, stop or delete operation on one of the user's games
+func GetOperation(c *gin.Context) {
+	v, _ := c.Get(session.SessionUserIDKey)
+	userId := v.(uuid.UUID)
+
+	operationId, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		c.Error(apiErr.NewNotFoundError(gamev1Service.ErrOperationNotFound, v1Err.ErrOperationNotFound))
+		return
+	}
+
+	var operation gamev1Resource.Operation
+
+	if err := gamev1Service.GetOperation(&operation, userId, operationId); err != nil {
+		if err == gamev1Service.ErrOperationNotFound {
+			c.Error(apiErr.NewNotFoundError(err, v1Err.ErrOperationNotFound))
+		} else {
+			c.Error(apiErr.NewInternalServerError(err, v1Err.ErrRetrievingOperation))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, operation)
+}
@@ -0,0 +1,97 @@
+package v1Controllers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+
+	v1Err "agones-minecraft/errors/v1"
+	"agones-minecraft/middleware/session"
+	gamev1Model "agones-minecraft/models/v1/game"
+	apiErr "agones-minecraft/resources/api/v1/errors"
+	gamev1Resource "agones-minecraft/resources/api/v1/game"
+	gamev1Service "agones-minecraft/services/api/v1/game"
+	"agones-minecraft/services/mc"
+)
+
+func GetWhitelist(c *gin.Context) {
+	getPlayers(c, gamev1Model.Whitelist)
+}
+
+func SetWhitelist(c *gin.Context) {
+	setPlayers(c, gamev1Model.Whitelist)
+}
+
+func GetOps(c *gin.Context) {
+	getPlayers(c, gamev1Model.Ops)
+}
+
+func SetOps(c *gin.Context) {
+	setPlayers(c, gamev1Model.Ops)
+}
+
+func getPlayers(c *gin.Context, list gamev1Model.PlayerList) {
+	v, _ := c.Get(session.SessionUserIDKey)
+	userId := v.(uuid.UUID)
+
+	name := c.Param("name")
+
+	players := []*gamev1Resource.Player{}
+
+	if err := gamev1Service.GetPlayers(&players, list, userId, name); err != nil {
+		if err == gamev1Service.ErrGameServerNotFound {
+			c.Error(apiErr.NewNotFoundError(err, v1Err.ErrGameNotFound))
+		} else {
+			c.Error(apiErr.NewInternalServerError(err, v1Err.ErrListingPlayers))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, players)
+}
+
+func setPlayers(c *gin.Context, list gamev1Model.PlayerList) {
+	v, _ := c.Get(session.SessionUserIDKey)
+	userId := v.(uuid.UUID)
+
+	name := c.Param("name")
+
+	var body gamev1Resource.PlayerListBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		var verrs validator.ValidationErrors
+		if errors.As(err, &verrs) {
+			c.Errors = append(c.Errors, apiErr.NewValidationError(verrs, v1Err.ErrSetPlayersValidation)...)
+		} else if err == io.EOF {
+			c.Error(apiErr.NewBadRequestError(ErrMissingRequestBody, v1Err.ErrMissingRequestBody))
+		} else {
+			c.Error(apiErr.NewInternalServerError(err, v1Err.ErrMalformedJSON))
+		}
+		return
+	}
+
+	players := []*gamev1Resource.Player{}
+
+	if err := gamev1Service.SetPlayers(&players, list, userId, name, body); err != nil {
+		switch e := err.(type) {
+		case *mc.ErrMcUserNotFound:
+			c.Error(apiErr.NewBadRequestError(e, v1Err.ErrMcUserNotFound))
+		case *mc.ErrUnmarshalingMCAccountJSON:
+			c.Error(apiErr.NewInternalServerError(e, v1Err.ErrUnmarshalingMCAccountJSON))
+		default:
+			if err == gamev1Service.ErrGameServerNotFound {
+				c.Error(apiErr.NewNotFoundError(err, v1Err.ErrGameNotFound))
+			} else if err == gamev1Service.ErrPlayerListsUnsupported {
+				c.Error(apiErr.NewBadRequestError(err, v1Err.ErrPlayerListsUnsupported))
+			} else {
+				c.Error(apiErr.NewInternalServerError(err, v1Err.ErrSettingPlayers))
+			}
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, players)
+}
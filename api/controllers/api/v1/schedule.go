@@ -0,0 +1,98 @@
+package v1Controllers
+
+import (
+	"errors"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+	"github.com/google/uuid"
+
+	v1Err "agones-minecraft/errors/v1"
+	"agones-minecraft/middleware/session"
+	apiErr "agones-minecraft/resources/api/v1/errors"
+	gamev1Resource "agones-minecraft/resources/api/v1/game"
+	gamev1Service "agones-minecraft/services/api/v1/game"
+)
+
+func ListSchedules(c *gin.Context) {
+	v, _ := c.Get(session.SessionUserIDKey)
+	userId := v.(uuid.UUID)
+
+	name := c.Param("name")
+
+	schedules := []*gamev1Resource.Schedule{}
+
+	if err := gamev1Service.ListSchedules(&schedules, userId, name); err != nil {
+		if err == gamev1Service.ErrGameServerNotFound {
+			c.Error(apiErr.NewNotFoundError(err, v1Err.ErrGameNotFound))
+		} else {
+			c.Error(apiErr.NewInternalServerError(err, v1Err.ErrListingSchedules))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, schedules)
+}
+
+func CreateSchedule(c *gin.Context) {
+	v, _ := c.Get(session.SessionUserIDKey)
+	userId := v.(uuid.UUID)
+
+	name := c.Param("name")
+
+	var body gamev1Resource.ScheduleBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		var verrs validator.ValidationErrors
+		if errors.As(err, &verrs) {
+			c.Errors = append(c.Errors, apiErr.NewValidationError(verrs, v1Err.ErrCreateScheduleValidation)...)
+		} else if err == io.EOF {
+			c.Error(apiErr.NewBadRequestError(ErrMissingRequestBody, v1Err.ErrMissingRequestBody))
+		} else {
+			c.Error(apiErr.NewInternalServerError(err, v1Err.ErrMalformedJSON))
+		}
+		return
+	}
+
+	var schedule gamev1Resource.Schedule
+
+	if err := gamev1Service.CreateSchedule(&schedule, userId, name, body); err != nil {
+		if err == gamev1Service.ErrGameServerNotFound {
+			c.Error(apiErr.NewNotFoundError(err, v1Err.ErrGameNotFound))
+		} else if err == gamev1Service.ErrTooManySchedules {
+			c.Error(apiErr.NewBadRequestError(err, v1Err.ErrTooManySchedules))
+		} else {
+			c.Error(apiErr.NewInternalServerError(err, v1Err.ErrCreatingSchedule))
+		}
+		return
+	}
+
+	c.JSON(http.StatusCreated, schedule)
+}
+
+func DeleteSchedule(c *gin.Context) {
+	v, _ := c.Get(session.SessionUserIDKey)
+	userId := v.(uuid.UUID)
+
+	name := c.Param("name")
+
+	scheduleId, err := uuid.Parse(c.Param("schedule"))
+	if err != nil {
+		c.Error(apiErr.NewNotFoundError(gamev1Service.ErrScheduleNotFound, v1Err.ErrScheduleNotFound))
+		return
+	}
+
+	if err := gamev1Service.DeleteSchedule(userId, name, scheduleId); err != nil {
+		if err == gamev1Service.ErrGameServerNotFound {
+			c.Error(apiErr.NewNotFoundError(err, v1Err.ErrGameNotFound))
+		} else if err == gamev1Service.ErrScheduleNotFound {
+			c.Error(apiErr.NewNotFoundError(err, v1Err.ErrScheduleNotFound))
+		} else {
+			c.Error(apiErr.NewInternalServerError(err, v1Err.ErrDeletingSchedule))
+		}
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
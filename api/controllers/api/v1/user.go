@@ -15,6 +15,7 @@ import (
 	userv1Model "agones-minecraft/models/v1/user"
 	apiErr "agones-minecraft/resources/api/v1/errors"
 	userv1Resource "agones-minecraft/resources/api/v1/user"
+	gamev1Service "agones-minecraft/services/api/v1/game"
 	userv1Service "agones-minecraft/services/api/v1/user"
 	"agones-minecraft/services/mc"
 )
@@ -47,6 +48,12 @@ func GetMe(c *gin.Context) {
 		}
 	}
 
+	var plan userv1Resource.Plan
+	if err := gamev1Service.GetPlan(&plan, userId); err != nil {
+		c.Error(apiErr.NewInternalServerError(err, v1Err.ErrRetrievingPlan))
+		return
+	}
+
 	foundUser := userv1Resource.User{
 		ID:            user.ID,
 		Email:         user.TwitchAccount.Email,
@@ -57,6 +64,7 @@ func GetMe(c *gin.Context) {
 			TwitchPicture:  user.TwitchAccount.Picture,
 		},
 		MCAccount: mcAccount,
+		Plan:      &plan,
 		LastLogin: user.LastLogin,
 		CreatedAt: user.CreatedAt,
 		UpdatedAt: user.UpdatedAt,
@@ -0,0 +1,94 @@
+package v1Controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+
+	"agones-minecraft/config"
+	v1Err "agones-minecraft/errors/v1"
+	"agones-minecraft/middleware/session"
+	apiErr "agones-minecraft/resources/api/v1/errors"
+	gamev1Resource "agones-minecraft/resources/api/v1/game"
+	gamev1Service "agones-minecraft/services/api/v1/game"
+	"agones-minecraft/services/world"
+)
+
+// Multipart form field holding uploaded world archives
+const worldFormField string = "world"
+
+// Room for multipart headers and boundaries on top of the upload limit
+const multipartOverhead int64 = 1 << 20
+
+var (
+	ErrMissingWorld  error = errors.New("missing world archive in form field 'world'")
+	ErrWorldTooLarge error = errors.New("world archive is larger than the upload limit")
+)
+
+// Errors for archives that are not valid worlds
+var invalidWorldErrors = map[error]bool{
+	world.ErrUnsupportedFormat: true,
+	world.ErrInvalidArchive:    true,
+	world.ErrUnsafeEntry:       true,
+	world.ErrTooManyEntries:    true,
+	world.ErrMissingLevelDat:   true,
+	world.ErrMissingBedrockDB:  true,
+}
+
+// Uploads a world archive that is loaded the next time the game is started
+func UploadWorld(c *gin.Context) {
+	v, _ := c.Get(session.SessionUserIDKey)
+	userId := v.(uuid.UUID)
+
+	name := c.Param("name")
+
+	limit := config.GetWorldUploadLimit()
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, limit+multipartOverhead)
+
+	header, err := c.FormFile(worldFormField)
+	if err != nil {
+		if c.Request.ContentLength > limit+multipartOverhead {
+			c.Error(apiErr.NewBadRequestError(ErrWorldTooLarge, v1Err.ErrWorldUploadTooLarge))
+		} else {
+			c.Error(apiErr.NewBadRequestError(ErrMissingWorld, v1Err.ErrInvalidWorldUpload))
+		}
+		return
+	}
+
+	if header.Size > limit {
+		c.Error(apiErr.NewBadRequestError(ErrWorldTooLarge, v1Err.ErrWorldUploadTooLarge))
+		return
+	}
+
+	format, err := world.FormatFromName(header.Filename)
+	if err != nil {
+		c.Error(apiErr.NewBadRequestError(err, v1Err.ErrInvalidWorldUpload))
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		c.Error(apiErr.NewInternalServerError(err, v1Err.ErrUploadingWorld))
+		return
+	}
+	defer file.Close()
+
+	var game gamev1Resource.Game
+
+	if err := gamev1Service.UploadWorld(&game, userId, name, file, header.Size, format); err != nil {
+		if err == gamev1Service.ErrGameServerNotFound {
+			c.Error(apiErr.NewNotFoundError(err, v1Err.ErrGameNotFound))
+		} else if err == world.ErrTooLarge {
+			c.Error(apiErr.NewBadRequestError(err, v1Err.ErrWorldUploadTooLarge))
+		} else if invalidWorldErrors[err] {
+			c.Error(apiErr.NewBadRequestError(err, v1Err.ErrInvalidWorldUpload))
+		} else {
+			c.Error(apiErr.NewInternalServerError(err, v1Err.ErrUploadingWorld))
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, game)
+}
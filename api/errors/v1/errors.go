@@ -101,4 +101,94 @@ const (
 	ErrDeletingGameFromK8s ErrorID = "8662"
 	// error deleting game from DB
 	ErrDeletingGameFromDB ErrorID = "cd6d"
+	// game server is already stopped
+	ErrGameAlreadyStopped ErrorID = "65f8"
+	// game server is already started
+	ErrGameAlreadyStarted ErrorID = "74dd"
+	// error backing up game world before stopping
+	ErrBackingUpGame ErrorID = "13bd"
+	// error stopping game
+	ErrStoppingGame ErrorID = "8c99"
+	// error starting game
+	ErrStartingGame ErrorID = "db7f"
+	// update game settings validation error
+	ErrUpdateGameValidation ErrorID = "4318"
+	// error updating game settings
+	ErrUpdatingGame ErrorID = "4c58"
+	// server type not available for edition or version
+	ErrInvalidServerType ErrorID = "53d6"
+	// error listing game mods
+	ErrListingGameMods ErrorID = "1708"
+	// add game mod validation error
+	ErrAddGameModValidation ErrorID = "0859"
+	// mod already added to game
+	ErrModAlreadyAdded ErrorID = "5497"
+	// error resolving mod from source
+	ErrResolvingMod ErrorID = "dac2"
+	// error adding game mod
+	ErrAddingGameMod ErrorID = "387c"
+	// invalid mod id param
+	ErrInvalidModID ErrorID = "2650"
+	// game mod not found
+	ErrGameModNotFound ErrorID = "4335"
+	// error removing game mod
+	ErrRemovingGameMod ErrorID = "bb0d"
+	// error listing game whitelist or operators
+	ErrListingPlayers ErrorID = "4b24"
+	// set game whitelist or operators validation error
+	ErrSetPlayersValidation ErrorID = "4f5d"
+	// whitelist and operators not supported for game edition
+	ErrPlayerListsUnsupported ErrorID = "7096"
+	// error setting game whitelist or operators
+	ErrSettingPlayers ErrorID = "4867"
+	// console not supported for game edition
+	ErrConsoleUnsupported ErrorID = "27a1"
+	// game server is not online
+	ErrGameNotOnline ErrorID = "b888"
+	// console command not allowed for user
+	ErrCommandNotAllowed ErrorID = "f2e3"
+	// error opening game console
+	ErrOpeningConsole ErrorID = "188a"
+	// run command validation error
+	ErrRunCommandValidation ErrorID = "e21f"
+	// error running console command
+	ErrRunningCommand ErrorID = "7b6a"
+	// error listing game backups
+	ErrListingBackups ErrorID = "e8d8"
+	// error creating game backup
+	ErrCreatingBackup ErrorID = "376a"
+	// game backup not found
+	ErrBackupNotFound ErrorID = "d2c0"
+	// error signing game backup download url
+	ErrGettingBackupDownload ErrorID = "b4cb"
+	// error restoring game backup
+	ErrRestoringBackup ErrorID = "e589"
+	// world upload missing or invalid archive
+	ErrInvalidWorldUpload ErrorID = "3c49"
+	// world upload larger than upload limit
+	ErrWorldUploadTooLarge ErrorID = "33af"
+	// error uploading game world
+	ErrUploadingWorld ErrorID = "1021"
+	// error listing game schedules
+	ErrListingSchedules ErrorID = "7c79"
+	// create game schedule validation error
+	ErrCreateScheduleValidation ErrorID = "1dc7"
+	// game has too many schedules
+	ErrTooManySchedules ErrorID = "12da"
+	// error creating game schedule
+	ErrCreatingSchedule ErrorID = "bc2a"
+	// game schedule not found
+	ErrScheduleNotFound ErrorID = "5560"
+	// error deleting game schedule
+	ErrDeletingSchedule ErrorID = "e470"
+	// game exceeds user's plan limits
+	ErrQuotaExceeded ErrorID = "0bac"
+	// error retrieving user plan
+	ErrRetrievingPlan ErrorID = "eb6b"
+	// game must be stopped to change its tier
+	ErrGameNotStopped ErrorID = "ea70"
+	// operation not found
+	ErrOperationNotFound ErrorID = "add7"
+	// error retrieving operation
+	ErrRetrievingOperation ErrorID = "93dc"
 )
@@ -10,6 +10,10 @@ import (
 
 type Edition string
 type GameState string
+type Difficulty string
+type GameMode string
+type LevelType string
+type ServerType string
 
 const (
 	JavaEdition    Edition = "java"
@@ -17,17 +21,92 @@ const (
 
 	On  GameState = "ON"
 	Off GameState = "OFF"
+
+	Peaceful Difficulty = "peaceful"
+	Easy     Difficulty = "easy"
+	Normal   Difficulty = "normal"
+	Hard     Difficulty = "hard"
+
+	Survival  GameMode = "survival"
+	Creative  GameMode = "creative"
+	Adventure GameMode = "adventure"
+	Spectator GameMode = "spectator"
+
+	DefaultLevel     LevelType = "default"
+	FlatLevel        LevelType = "flat"
+	LargeBiomesLevel LevelType = "largeBiomes"
+	AmplifiedLevel   LevelType = "amplified"
+
+	LatestVersion string = "LATEST"
+
+	DefaultBackupCron string = "0 */6 * * *"
+
+	// Minutes without players online before a game is stopped. Zero never stops the game so owners opt in
+	DefaultIdleTimeout int = 0
+
+	Vanilla ServerType = "vanilla"
+	Paper   ServerType = "paper"
+	Spigot  ServerType = "spigot"
+	Fabric  ServerType = "fabric"
+	Forge   ServerType = "forge"
 )
 
 type Game struct {
 	model.Model
-	UserID  uuid.UUID `pg:"type:uuid,notnull,unique"`
-	Name    string    `pg:"type:varchar(60)"`
-	MOTD    string    `pg:"type:varchar(59)"`
-	Slots   int       `pg:"default:10"`
-	Address string    `pg:"type:varchar(63),notnull"`
-	Edition Edition   `pg:"type:varchar(25),notnull"`
-	State   GameState `pg:"type:varchar(25),default:off,notnull"`
+	UserID  uuid.UUID  `pg:"type:uuid,notnull,unique"`
+	Name    string     `pg:"type:varchar(60)"`
+	Address string     `pg:"type:varchar(63),notnull"`
+	Edition Edition    `pg:"type:varchar(25),notnull"`
+	State   GameState  `pg:"type:varchar(25),default:off,notnull"`
+	Type    ServerType `pg:"type:varchar(25),default:'vanilla',notnull"`
+	Settings
+	Persistent bool `pg:"default:false,notnull,use_zero"`
+	// World backup loaded the next time the game is started
+	RestoreBackup string
+}
+
+// Server properties passed to the Minecraft server
+type Settings struct {
+	MOTD        string     `pg:"type:varchar(59)"`
+	Slots       int        `pg:"default:10"`
+	Difficulty  Difficulty `pg:"type:varchar(25),default:'easy',notnull"`
+	GameMode    GameMode   `pg:"type:varchar(25),default:'survival',notnull"`
+	Version     string     `pg:"type:varchar(25),default:'LATEST',notnull"`
+	Seed        string     `pg:"type:varchar(64)"`
+	LevelType   LevelType  `pg:"type:varchar(25),default:'default',notnull"`
+	Whitelist   bool       `pg:"default:false,notnull,use_zero"`
+	IdleTimeout int        `pg:"default:0,notnull,use_zero"`
+	Tier        string     `pg:"type:varchar(25),default:'small',notnull"`
+	BackupSettings
+}
+
+// World backup schedule and retention. Retention keeps the newest BackupKeepLast backups plus the newest
+// backup of each of the last BackupKeepDaily days and BackupKeepWeekly weeks. All zero keeps every backup
+type BackupSettings struct {
+	BackupEnabled    bool   `pg:"default:true,notnull,use_zero"`
+	BackupCron       string `pg:"type:varchar(100),default:'0 */6 * * *',notnull"`
+	BackupKeepLast   int    `pg:"default:10,notnull,use_zero"`
+	BackupKeepDaily  int    `pg:"default:7,notnull,use_zero"`
+	BackupKeepWeekly int    `pg:"default:4,notnull,use_zero"`
+}
+
+// Returns settings for new games
+func DefaultSettings() Settings {
+	return Settings{
+		Slots:       10,
+		Difficulty:  Easy,
+		GameMode:    Survival,
+		Version:     LatestVersion,
+		LevelType:   DefaultLevel,
+		IdleTimeout: DefaultIdleTimeout,
+		BackupSettings: BackupSettings{
+			BackupEnabled:    true,
+			BackupCron:       DefaultBackupCron,
+			BackupKeepLast:   10,
+			BackupKeepDaily:  7,
+			BackupKeepWeekly: 4,
+		},
+	}
 }
 
 func (g *Game) GetResourceName() string {
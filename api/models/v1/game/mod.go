@@ -0,0 +1,33 @@
+package game
+
+import (
+	"github.com/google/uuid"
+
+	"agones-minecraft/models/v1/model"
+)
+
+type ModSource string
+type ModKind string
+
+const (
+	CatalogSource  ModSource = "catalog"
+	ModrinthSource ModSource = "modrinth"
+	SpigotSource   ModSource = "spigot"
+
+	// Fabric and Forge mods
+	ModContent ModKind = "mod"
+	// Paper and Spigot plugins
+	PluginContent ModKind = "plugin"
+)
+
+// Mod or plugin artifact downloaded by the server on start
+type GameMod struct {
+	model.Model
+	GameID    uuid.UUID `pg:"type:uuid,notnull"`
+	Source    ModSource `pg:"type:varchar(25),notnull"`
+	ProjectID string    `pg:"type:varchar(100),notnull"`
+	Name      string    `pg:"type:varchar(100),notnull"`
+	Version   string    `pg:"type:varchar(100),notnull"`
+	Kind      ModKind   `pg:"type:varchar(25),notnull"`
+	URL       string    `pg:"type:text,notnull"`
+}
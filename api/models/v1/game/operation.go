@@ -0,0 +1,55 @@
+package game
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type OperationType string
+
+const (
+	CreateOperation OperationType = "create"
+	StartOperation  OperationType = "start"
+	StopOperation   OperationType = "stop"
+	DeleteOperation OperationType = "delete"
+)
+
+type OperationStatus string
+
+const (
+	// Waiting for the GameServer to be created or deleted
+	OperationQueued OperationStatus = "queued"
+	// GameServer created and waiting for its pod to be placed on a node
+	OperationScheduled OperationStatus = "scheduled"
+	// Pod placed on a node and pulling images or restoring the world
+	OperationPodStarting OperationStatus = "pod_starting"
+	// Server running and generating or loading the world
+	OperationWorldLoading OperationStatus = "world_loading"
+	// Server online and accepting players
+	OperationReady OperationStatus = "ready"
+	// GameServer stopped or deleted
+	OperationDone   OperationStatus = "done"
+	OperationFailed OperationStatus = "failed"
+)
+
+// Tracks the progress of a change to a game's GameServer. Operations are updated from GameServer
+// changes until they finish. Starting a new operation for a game supersedes its unfinished operations
+type Operation struct {
+	tableName struct{}        `pg:"operations,alias:operation"`
+	ID        uuid.UUID       `pg:"type:uuid,default:uuid_generate_v4()"`
+	GameID    uuid.UUID       `pg:"type:uuid,notnull"`
+	UserID    uuid.UUID       `pg:"type:uuid,notnull"`
+	Type      OperationType   `pg:"type:varchar(10),notnull"`
+	Status    OperationStatus `pg:"type:varchar(20),default:'queued',notnull"`
+	// Why the operation failed, reported by Agones where available
+	Reason     string    `pg:"type:text"`
+	CreatedAt  time.Time `pg:"default:now(),notnull"`
+	UpdatedAt  time.Time `pg:"default:now(),notnull"`
+	FinishedAt time.Time
+}
+
+// Whether the operation is ready, done or failed
+func (o *Operation) IsFinished() bool {
+	return o.Status == OperationReady || o.Status == OperationDone || o.Status == OperationFailed
+}
@@ -0,0 +1,41 @@
+package game
+
+import (
+	"time"
+
+	agonesv1 "agones.dev/agones/pkg/apis/agones/v1"
+	"github.com/google/uuid"
+)
+
+type OutboxType string
+
+const (
+	CreateGameServer OutboxType = "create_game_server"
+	DeleteGameServer OutboxType = "delete_game_server"
+	StartGameServer  OutboxType = "start_game_server"
+	StopGameServer   OutboxType = "stop_game_server"
+)
+
+// Cluster change written in the same transaction as the game change it belongs to.
+// Messages are applied in order by the outbox worker and retried until they succeed or fail permanently
+type OutboxMessage struct {
+	tableName struct{}   `pg:"outbox,alias:outbox"`
+	ID        uuid.UUID  `pg:"type:uuid,default:uuid_generate_v4()"`
+	Type      OutboxType `pg:"type:varchar(25),notnull"`
+	GameID    uuid.UUID  `pg:"type:uuid,notnull"`
+	// GameServer name of the game
+	ResourceName string `pg:"type:varchar(100),notnull"`
+	// GameServer to create for create and start messages
+	GameServer *agonesv1.GameServer `pg:"type:jsonb"`
+	// Whether the game's world volume claim is created with its GameServer or deleted with its game
+	Persistent  bool      `pg:"default:false,notnull,use_zero"`
+	Attempts    int       `pg:"default:0,notnull,use_zero"`
+	LastError   string    `pg:"type:text"`
+	AvailableAt time.Time `pg:"default:now(),notnull"`
+	// When a worker claimed the message. Claims expire so messages of workers that died are applied again
+	ClaimedAt   time.Time
+	ProcessedAt time.Time
+	// When the message failed permanently. Failed messages are not retried
+	FailedAt  time.Time
+	CreatedAt time.Time `pg:"default:now(),notnull"`
+}
@@ -0,0 +1,24 @@
+package game
+
+import (
+	"github.com/google/uuid"
+
+	"agones-minecraft/models/v1/model"
+)
+
+type PlayerList string
+
+const (
+	Whitelist PlayerList = "game_whitelist"
+	Ops       PlayerList = "game_ops"
+)
+
+// Minecraft account on a game's whitelist or operator list.
+// Both lists share this model and are selected by table with PlayerList
+type GamePlayer struct {
+	model.Model
+	tableName struct{}  `pg:"alias:game_player"`
+	GameID    uuid.UUID `pg:"type:uuid,notnull"`
+	MCID      uuid.UUID `pg:"mc_id,type:uuid,notnull"`
+	Username  string    `pg:"type:varchar(16),notnull"`
+}
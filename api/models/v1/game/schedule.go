@@ -0,0 +1,22 @@
+package game
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	"agones-minecraft/models/v1/model"
+)
+
+// Window a game is online for. The game is started at each StartCron time and stopped at each StopCron time
+// in the schedule's timezone. e.g. 0 18 * * 5 to 0 23 * * 0 in America/Chicago
+type GameSchedule struct {
+	model.Model
+	GameID    uuid.UUID `pg:"type:uuid,notnull"`
+	StartCron string    `pg:"type:varchar(100),notnull"`
+	StopCron  string    `pg:"type:varchar(100),notnull"`
+	Timezone  string    `pg:"type:varchar(64),default:'UTC',notnull"`
+	Enabled   bool      `pg:"default:true,notnull,use_zero"`
+	// Start and stop times after this are due. Advanced by the scheduler after each applied run
+	CheckedAt time.Time `pg:"default:now(),notnull"`
+}
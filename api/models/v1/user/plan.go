@@ -0,0 +1,21 @@
+package user
+
+import (
+	"agones-minecraft/models/v1/model"
+)
+
+// Limits on the games a user can create and run. Zero limits are unlimited
+type Plan struct {
+	model.Model
+	Name string `pg:"type:varchar(25),notnull,unique"`
+	// Games a user can create
+	MaxGames int `pg:"default:0,notnull,use_zero"`
+	// Games a user can have online at once
+	MaxRunningGames int `pg:"default:0,notnull,use_zero"`
+	// Player slots per game
+	MaxSlots int `pg:"default:0,notnull,use_zero"`
+	// Memory in MiB across a user's online games
+	MaxMemory int `pg:"default:0,notnull,use_zero"`
+	// World backup storage in MiB across a user's games
+	MaxBackupStorage int `pg:"default:0,notnull,use_zero"`
+}
@@ -3,6 +3,8 @@ package user
 import (
 	"time"
 
+	"github.com/google/uuid"
+
 	"agones-minecraft/models/v1/game"
 	"agones-minecraft/models/v1/mc"
 	"agones-minecraft/models/v1/model"
@@ -16,4 +18,11 @@ type User struct {
 	TwitchAccount *twitch.TwitchAccount `pg:"rel:belongs-to"`
 	MCAccount     *mc.MCAccount         `pg:"rel:belongs-to"`
 	Games         []*game.Game          `pg:"rel:has-many"`
+	// Console commands the user may run. Empty uses the app wide allowed commands
+	ConsoleAllow []string `pg:",array"`
+	// Console commands the user may not run in addition to the app wide denied commands
+	ConsoleDeny []string `pg:",array"`
+	// Users without a plan are on the default plan
+	PlanID uuid.UUID `pg:"type:uuid"`
+	Plan   *Plan     `pg:"rel:has-one"`
 }
@@ -65,6 +65,18 @@ func NewUnauthorizedError(err error, id v1Err.ErrorID) *gin.Error {
 	}
 }
 
+func NewForbiddenError(err error, id v1Err.ErrorID) *gin.Error {
+	return &gin.Error{
+		Err:  err,
+		Type: gin.ErrorTypePublic,
+		Meta: &APIError{
+			Message:     err.Error(),
+			StatusCode:  http.StatusForbidden,
+			ReferenceID: id,
+		},
+	}
+}
+
 func NewNotFoundError(err error, id v1Err.ErrorID) *gin.Error {
 	return &gin.Error{
 		Err:  err,
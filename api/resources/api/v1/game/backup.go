@@ -0,0 +1,25 @@
+package game
+
+import (
+	"time"
+
+	"agones-minecraft/services/storage"
+)
+
+type Backup struct {
+	Name      string    `json:"name"`
+	Size      int64     `json:"size"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+type BackupDownload struct {
+	URL       string    `json:"url"`
+	ExpiresAt time.Time `json:"expiresAt"`
+}
+
+// Merge a non-nil stored backup object and the time it was taken into a backup api resource
+func (backup *Backup) MergeObject(object *storage.Object, createdAt time.Time) {
+	backup.Name = object.Name
+	backup.Size = object.Size
+	backup.CreatedAt = createdAt
+}
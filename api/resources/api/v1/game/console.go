@@ -0,0 +1,12 @@
+package game
+
+type CommandBody struct {
+	Command string `json:"command" binding:"required,max=1446"`
+}
+
+// Result of a console command. Error is set instead of Output when the command fails
+type CommandResult struct {
+	Command string `json:"command"`
+	Output  string `json:"output"`
+	Error   string `json:"error,omitempty"`
+}
@@ -0,0 +1,35 @@
+package game
+
+import (
+	agonesv1 "agones.dev/agones/pkg/apis/agones/v1"
+	"github.com/google/uuid"
+
+	gamev1Model "agones-minecraft/models/v1/game"
+	"agones-minecraft/services/k8s/agones"
+)
+
+// Change to a game's GameServer
+type GameEvent struct {
+	Type    agones.EventType          `json:"type"`
+	ID      uuid.UUID                 `json:"id"`
+	Name    string                    `json:"name"`
+	Edition gamev1Model.Edition       `json:"edition"`
+	Status  *agonesv1.GameServerState `json:"status"`
+	Address string                    `json:"address"`
+	Port    *int32                    `json:"port,omitempty"`
+}
+
+// Merge a GameServer event into a game event api resource
+func (event *GameEvent) MergeEvent(e agones.Event) {
+	gs := e.GameServer
+
+	event.Type = e.Type
+	event.ID = agones.GetUUID(gs)
+	event.Name = agones.GetName(gs)
+	event.Edition = agones.GetEdition(gs)
+	event.Status = agones.GetStatus(gs)
+	event.Address = agones.GetAddress(gs)
+	if event.Edition == gamev1Model.BedrockEdition {
+		event.Port = agones.GetPort(gs)
+	}
+}
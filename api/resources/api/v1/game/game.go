@@ -8,6 +8,7 @@ import (
 
 	gamev1Model "agones-minecraft/models/v1/game"
 	"agones-minecraft/services/k8s/agones"
+	"agones-minecraft/services/ping"
 )
 
 type Status string
@@ -20,22 +21,138 @@ const (
 )
 
 type CreateGameBody struct {
-	Name      string `json:"name" binding:"required,min=3,max=25"`
-	Subdomain string `json:"subdomain" binding:"required,hostname_rfc1123"`
+	Name       string                 `json:"name" binding:"required,min=3,max=25"`
+	Subdomain  string                 `json:"subdomain" binding:"required,hostname_rfc1123"`
+	Persistent bool                   `json:"persistent"`
+	Type       gamev1Model.ServerType `json:"type" binding:"omitempty,mcservertype"`
+	GameSettingsBody
+}
+
+// Optional game settings. Omitted settings are left unchanged
+type GameSettingsBody struct {
+	MOTD        *string                 `json:"motd" binding:"omitempty,max=59"`
+	Slots       *int                    `json:"slots" binding:"omitempty,min=1,max=100"`
+	Difficulty  *gamev1Model.Difficulty `json:"difficulty" binding:"omitempty,oneof=peaceful easy normal hard"`
+	GameMode    *gamev1Model.GameMode   `json:"gameMode" binding:"omitempty,oneof=survival creative adventure spectator"`
+	Version     *string                 `json:"version" binding:"omitempty,mcversion"`
+	Seed        *string                 `json:"seed" binding:"omitempty,max=64"`
+	LevelType   *gamev1Model.LevelType  `json:"levelType" binding:"omitempty,oneof=default flat largeBiomes amplified"`
+	Whitelist   *bool                   `json:"whitelist"`
+	IdleTimeout *int                    `json:"idleTimeout" binding:"omitempty,min=0,max=1440"`
+	Tier        *string                 `json:"tier" binding:"omitempty,tier"`
+	Backup      *BackupSettingsBody     `json:"backup"`
+}
+
+// Optional backup settings. Omitted settings are left unchanged
+type BackupSettingsBody struct {
+	Enabled    *bool   `json:"enabled"`
+	Cron       *string `json:"cron" binding:"omitempty,cron"`
+	KeepLast   *int    `json:"keepLast" binding:"omitempty,min=0,max=100"`
+	KeepDaily  *int    `json:"keepDaily" binding:"omitempty,min=0,max=100"`
+	KeepWeekly *int    `json:"keepWeekly" binding:"omitempty,min=0,max=100"`
+}
+
+// Applies provided backup settings to game backup settings
+func (body *BackupSettingsBody) Apply(settings *gamev1Model.BackupSettings) {
+	if body.Enabled != nil {
+		settings.BackupEnabled = *body.Enabled
+	}
+	if body.Cron != nil {
+		settings.BackupCron = *body.Cron
+	}
+	if body.KeepLast != nil {
+		settings.BackupKeepLast = *body.KeepLast
+	}
+	if body.KeepDaily != nil {
+		settings.BackupKeepDaily = *body.KeepDaily
+	}
+	if body.KeepWeekly != nil {
+		settings.BackupKeepWeekly = *body.KeepWeekly
+	}
+}
+
+// Applies provided settings to game settings
+func (body *GameSettingsBody) Apply(settings *gamev1Model.Settings) {
+	if body.MOTD != nil {
+		settings.MOTD = *body.MOTD
+	}
+	if body.Slots != nil {
+		settings.Slots = *body.Slots
+	}
+	if body.Difficulty != nil {
+		settings.Difficulty = *body.Difficulty
+	}
+	if body.GameMode != nil {
+		settings.GameMode = *body.GameMode
+	}
+	if body.Version != nil {
+		settings.Version = *body.Version
+	}
+	if body.Seed != nil {
+		settings.Seed = *body.Seed
+	}
+	if body.LevelType != nil {
+		settings.LevelType = *body.LevelType
+	}
+	if body.Whitelist != nil {
+		settings.Whitelist = *body.Whitelist
+	}
+	if body.IdleTimeout != nil {
+		settings.IdleTimeout = *body.IdleTimeout
+	}
+	if body.Tier != nil {
+		settings.Tier = *body.Tier
+	}
+	if body.Backup != nil {
+		body.Backup.Apply(&settings.BackupSettings)
+	}
 }
 
 type Game struct {
-	ID        uuid.UUID                 `json:"id"`
-	UserID    uuid.UUID                 `json:"-"`
-	Name      string                    `json:"name"`
-	Edition   gamev1Model.Edition       `json:"edition"`
-	State     gamev1Model.GameState     `json:"state,omitempty"`
-	MOTD      string                    `json:"motd"`
-	Slots     int                       `json:"slots"`
-	Status    *agonesv1.GameServerState `json:"status"`
-	Address   string                    `json:"address"`
-	Port      *int32                    `json:"port,omitempty"`
-	CreatedAt time.Time                 `json:"createdAt"`
+	ID            uuid.UUID                 `json:"id"`
+	UserID        uuid.UUID                 `json:"-"`
+	Name          string                    `json:"name"`
+	Edition       gamev1Model.Edition       `json:"edition"`
+	Type          gamev1Model.ServerType    `json:"type"`
+	State         gamev1Model.GameState     `json:"state,omitempty"`
+	MOTD          string                    `json:"motd"`
+	Slots         int                       `json:"slots"`
+	Difficulty    gamev1Model.Difficulty    `json:"difficulty"`
+	GameMode      gamev1Model.GameMode      `json:"gameMode"`
+	Version       string                    `json:"version"`
+	Seed          string                    `json:"seed"`
+	LevelType     gamev1Model.LevelType     `json:"levelType"`
+	Whitelist     bool                      `json:"whitelist"`
+	IdleTimeout   int                       `json:"idleTimeout"`
+	Tier          string                    `json:"tier"`
+	Backup        BackupSettings            `json:"backup"`
+	Status        *agonesv1.GameServerState `json:"status"`
+	Address       string                    `json:"address"`
+	Port          *int32                    `json:"port,omitempty"`
+	Persistent    bool                      `json:"persistent"`
+	RestoreBackup string                    `json:"restoreBackup,omitempty"`
+	Server        *ServerStatus             `json:"server,omitempty"`
+	Operation     *Operation                `json:"operation,omitempty"`
+	CreatedAt     time.Time                 `json:"createdAt"`
+}
+
+type BackupSettings struct {
+	Enabled    bool   `json:"enabled"`
+	Cron       string `json:"cron"`
+	KeepLast   int    `json:"keepLast"`
+	KeepDaily  int    `json:"keepDaily"`
+	KeepWeekly int    `json:"keepWeekly"`
+}
+
+// Live status reported by an online server
+type ServerStatus struct {
+	PlayersOnline int      `json:"playersOnline"`
+	PlayersMax    int      `json:"playersMax"`
+	Players       []string `json:"players,omitempty"`
+	Version       string   `json:"version"`
+	MOTD          string   `json:"motd"`
+	// Round trip latency from the API in milliseconds
+	Latency int64 `json:"latency"`
 }
 
 // Merge fields of a non-nil game model and a non-nil Agones GameServer resource
@@ -47,9 +164,27 @@ func (game *Game) MergeGame(gameModel *gamev1Model.Game, gs *agonesv1.GameServer
 		game.UserID = gameModel.UserID
 		game.Address = gameModel.Address
 		game.Edition = gameModel.Edition
+		game.Type = gameModel.Type
 		game.State = gameModel.State
 		game.MOTD = gameModel.MOTD
 		game.Slots = gameModel.Slots
+		game.Difficulty = gameModel.Difficulty
+		game.GameMode = gameModel.GameMode
+		game.Version = gameModel.Version
+		game.Seed = gameModel.Seed
+		game.LevelType = gameModel.LevelType
+		game.Whitelist = gameModel.Whitelist
+		game.IdleTimeout = gameModel.IdleTimeout
+		game.Tier = gameModel.Tier
+		game.Backup = BackupSettings{
+			Enabled:    gameModel.BackupEnabled,
+			Cron:       gameModel.BackupCron,
+			KeepLast:   gameModel.BackupKeepLast,
+			KeepDaily:  gameModel.BackupKeepDaily,
+			KeepWeekly: gameModel.BackupKeepWeekly,
+		}
+		game.Persistent = gameModel.Persistent
+		game.RestoreBackup = gameModel.RestoreBackup
 		game.CreatedAt = gameModel.CreatedAt
 	}
 
@@ -60,3 +195,15 @@ func (game *Game) MergeGame(gameModel *gamev1Model.Game, gs *agonesv1.GameServer
 		}
 	}
 }
+
+// Merge a non-nil server status into a game api resource
+func (game *Game) MergeServerStatus(status *ping.Status) {
+	game.Server = &ServerStatus{
+		PlayersOnline: status.PlayersOnline,
+		PlayersMax:    status.PlayersMax,
+		Players:       status.Players,
+		Version:       status.Version,
+		MOTD:          status.MOTD,
+		Latency:       status.Latency.Milliseconds(),
+	}
+}
@@ -0,0 +1,38 @@
+package game
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	gamev1Model "agones-minecraft/models/v1/game"
+)
+
+type AddModBody struct {
+	Source gamev1Model.ModSource `json:"source" binding:"required,oneof=catalog modrinth spigot"`
+	// Catalog slug, Modrinth project ID or slug, or SpigotMC resource ID
+	ID string `json:"id" binding:"required,max=100"`
+}
+
+type GameMod struct {
+	ID        uuid.UUID             `json:"id"`
+	Source    gamev1Model.ModSource `json:"source"`
+	ProjectID string                `json:"projectId"`
+	Name      string                `json:"name"`
+	Version   string                `json:"version"`
+	Kind      gamev1Model.ModKind   `json:"kind"`
+	URL       string                `json:"url"`
+	CreatedAt time.Time             `json:"createdAt"`
+}
+
+// Merge fields of a non-nil game mod model into a game mod api resource
+func (mod *GameMod) MergeGameMod(modModel *gamev1Model.GameMod) {
+	mod.ID = modModel.ID
+	mod.Source = modModel.Source
+	mod.ProjectID = modModel.ProjectID
+	mod.Name = modModel.Name
+	mod.Version = modModel.Version
+	mod.Kind = modModel.Kind
+	mod.URL = modModel.URL
+	mod.CreatedAt = modModel.CreatedAt
+}
@@ -0,0 +1,36 @@
+package game
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	gamev1Model "agones-minecraft/models/v1/game"
+)
+
+type Operation struct {
+	ID         uuid.UUID                   `json:"id"`
+	GameID     uuid.UUID                   `json:"gameId"`
+	Type       gamev1Model.OperationType   `json:"type"`
+	Status     gamev1Model.OperationStatus `json:"status"`
+	Reason     string                      `json:"reason,omitempty"`
+	CreatedAt  time.Time                   `json:"createdAt"`
+	UpdatedAt  time.Time                   `json:"updatedAt"`
+	FinishedAt *time.Time                  `json:"finishedAt,omitempty"`
+}
+
+// Merge fields of a non-nil operation model into an operation api resource
+func (operation *Operation) MergeOperation(operationModel *gamev1Model.Operation) {
+	operation.ID = operationModel.ID
+	operation.GameID = operationModel.GameID
+	operation.Type = operationModel.Type
+	operation.Status = operationModel.Status
+	operation.Reason = operationModel.Reason
+	operation.CreatedAt = operationModel.CreatedAt
+	operation.UpdatedAt = operationModel.UpdatedAt
+
+	if !operationModel.FinishedAt.IsZero() {
+		finishedAt := operationModel.FinishedAt
+		operation.FinishedAt = &finishedAt
+	}
+}
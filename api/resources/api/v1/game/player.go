@@ -0,0 +1,22 @@
+package game
+
+import (
+	"github.com/google/uuid"
+
+	gamev1Model "agones-minecraft/models/v1/game"
+)
+
+type PlayerListBody struct {
+	Players []string `json:"players" binding:"required,max=100,dive,mcusername"`
+}
+
+type Player struct {
+	ID       uuid.UUID `json:"id"`
+	Username string    `json:"username"`
+}
+
+// Merge fields of a non-nil game player model into a player api resource
+func (player *Player) MergeGamePlayer(playerModel *gamev1Model.GamePlayer) {
+	player.ID = playerModel.MCID
+	player.Username = playerModel.Username
+}
@@ -0,0 +1,45 @@
+package game
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+
+	gamev1Model "agones-minecraft/models/v1/game"
+)
+
+type ScheduleBody struct {
+	Start    string `json:"start" binding:"required,cron"`
+	Stop     string `json:"stop" binding:"required,cron"`
+	Timezone string `json:"timezone" binding:"omitempty,timezone"`
+	Enabled  *bool  `json:"enabled"`
+}
+
+type Schedule struct {
+	ID        uuid.UUID  `json:"id"`
+	Start     string     `json:"start"`
+	Stop      string     `json:"stop"`
+	Timezone  string     `json:"timezone"`
+	Enabled   bool       `json:"enabled"`
+	NextStart *time.Time `json:"nextStart,omitempty"`
+	NextStop  *time.Time `json:"nextStop,omitempty"`
+	CreatedAt time.Time  `json:"createdAt"`
+}
+
+// Merge fields of a non-nil game schedule model into a schedule api resource.
+// Next start and stop times are omitted when zero
+func (schedule *Schedule) MergeGameSchedule(scheduleModel *gamev1Model.GameSchedule, nextStart time.Time, nextStop time.Time) {
+	schedule.ID = scheduleModel.ID
+	schedule.Start = scheduleModel.StartCron
+	schedule.Stop = scheduleModel.StopCron
+	schedule.Timezone = scheduleModel.Timezone
+	schedule.Enabled = scheduleModel.Enabled
+	schedule.CreatedAt = scheduleModel.CreatedAt
+
+	if !nextStart.IsZero() {
+		schedule.NextStart = &nextStart
+	}
+	if !nextStop.IsZero() {
+		schedule.NextStop = &nextStop
+	}
+}
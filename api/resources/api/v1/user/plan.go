@@ -0,0 +1,41 @@
+package user
+
+import (
+	userv1Model "agones-minecraft/models/v1/user"
+)
+
+// A user's plan limits and their current usage. Zero limits are unlimited
+type Plan struct {
+	Name   string     `json:"name"`
+	Limits PlanLimits `json:"limits"`
+	Usage  PlanUsage  `json:"usage"`
+}
+
+// Memory and backup storage are in MiB
+type PlanLimits struct {
+	Games         int `json:"games"`
+	RunningGames  int `json:"runningGames"`
+	Slots         int `json:"slots"`
+	Memory        int `json:"memory"`
+	BackupStorage int `json:"backupStorage"`
+}
+
+// Memory and backup storage are in MiB
+type PlanUsage struct {
+	Games         int `json:"games"`
+	RunningGames  int `json:"runningGames"`
+	Memory        int `json:"memory"`
+	BackupStorage int `json:"backupStorage"`
+}
+
+// Merge fields of a non-nil plan model into a plan api resource
+func (plan *Plan) MergePlan(planModel *userv1Model.Plan) {
+	plan.Name = planModel.Name
+	plan.Limits = PlanLimits{
+		Games:         planModel.MaxGames,
+		RunningGames:  planModel.MaxRunningGames,
+		Slots:         planModel.MaxSlots,
+		Memory:        planModel.MaxMemory,
+		BackupStorage: planModel.MaxBackupStorage,
+	}
+}
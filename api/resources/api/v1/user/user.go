@@ -12,6 +12,7 @@ type User struct {
 	EmailVerified bool           `json:"emailVerified"`
 	TwitchAccount *TwitchAccount `json:"twitchAccount"`
 	MCAccount     *MCAccount     `json:"mcAccount"`
+	Plan          *Plan          `json:"plan"`
 	LastLogin     time.Time      `json:"lastLogin"`
 	CreatedAt     time.Time      `json:"createdAt"`
 	UpdatedAt     time.Time      `json:"updatedAt"`
@@ -87,11 +87,46 @@ func AddV1Router(r *gin.Engine) {
 	{
 		game.Use(session.Authorizer())
 		game.GET("/list", v1Controllers.ListGamesForUser)
+		game.GET("/events", v1Controllers.GameEvents)
 		game.GET("/:name", v1Controllers.GetGame)
 
 		game.POST("/java", v1Controllers.CreateJava)
 		game.POST("/bedrock", v1Controllers.CreateBedrock)
 
+		game.POST("/:name/stop", v1Controllers.StopGame)
+		game.POST("/:name/start", v1Controllers.StartGame)
+
+		game.GET("/:name/mods", v1Controllers.ListGameMods)
+		game.POST("/:name/mods", v1Controllers.AddGameMod)
+		game.DELETE("/:name/mods/:modId", v1Controllers.RemoveGameMod)
+
+		game.GET("/:name/whitelist", v1Controllers.GetWhitelist)
+		game.PUT("/:name/whitelist", v1Controllers.SetWhitelist)
+		game.GET("/:name/ops", v1Controllers.GetOps)
+		game.PUT("/:name/ops", v1Controllers.SetOps)
+
+		game.GET("/:name/console", v1Controllers.Console)
+		game.POST("/:name/command", v1Controllers.RunCommand)
+
+		game.GET("/:name/backups", v1Controllers.ListBackups)
+		game.POST("/:name/backups", v1Controllers.CreateBackup)
+		game.GET("/:name/backups/:backup/download", v1Controllers.GetBackupDownload)
+		game.POST("/:name/backups/:backup/restore", v1Controllers.RestoreBackup)
+
+		game.POST("/:name/world", v1Controllers.UploadWorld)
+
+		game.GET("/:name/schedules", v1Controllers.ListSchedules)
+		game.POST("/:name/schedules", v1Controllers.CreateSchedule)
+		game.DELETE("/:name/schedules/:schedule", v1Controllers.DeleteSchedule)
+
+		game.PATCH("/:name", v1Controllers.UpdateGame)
+
 		game.DELETE("/:name", v1Controllers.DeleteGame)
 	}
+
+	operations := v1.Group("/operations")
+	{
+		operations.Use(session.Authorizer())
+		operations.GET("/:id", v1Controllers.GetOperation)
+	}
 }
@@ -0,0 +1,209 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"agones-minecraft/db"
+	gamev1Model "agones-minecraft/models/v1/game"
+	gamev1Resource "agones-minecraft/resources/api/v1/game"
+	"agones-minecraft/services/k8s/agones"
+	"agones-minecraft/services/storage"
+)
+
+// Time signed backup download URLs are valid for
+const BackupDownloadExpiry time.Duration = time.Minute * 15
+
+var (
+	ErrBackupNotFound error = errors.New("backup not found")
+)
+
+// Lists a game's world backups from newest to oldest
+func ListBackups(backups *[]*gamev1Resource.Backup, userId uuid.UUID, name string) error {
+	foundGame, err := findGame(userId, name)
+	if err != nil {
+		return err
+	}
+
+	foundBackups, err := getBackups(foundGame)
+	if err != nil {
+		return err
+	}
+
+	*backups = append(*backups, foundBackups...)
+
+	return nil
+}
+
+// Backs up an online game's world immediately and returns the new backup
+func CreateBackup(backup *gamev1Resource.Backup, userId uuid.UUID, name string) error {
+	foundGame, err := findGame(userId, name)
+	if err != nil {
+		return err
+	}
+
+	gs, err := agones.Client().GetForUser(foundGame.GetResourceName(), userId)
+	if err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return ErrGameNotOnline
+		}
+		return err
+	}
+
+	if !agones.IsOnline(gs) {
+		return ErrGameNotOnline
+	}
+
+	// flush the world to disk before it is archived. mc-backup saves over RCON as well
+	// so a failed save is not fatal
+	if foundGame.Edition == gamev1Model.JavaEdition {
+		if err := runCommands(gs, []string{"save-all flush"}); err != nil {
+			zap.L().Warn("error saving world before backup", zap.String("game", foundGame.Name), zap.Error(err))
+		}
+	}
+
+	if err := agones.Client().BackupWorld(gs); err != nil {
+		return &ErrBackingUpGame{err}
+	}
+
+	foundBackups, err := getBackups(foundGame)
+	if err != nil {
+		return err
+	}
+
+	if len(foundBackups) == 0 {
+		return &ErrBackingUpGame{ErrBackupNotFound}
+	}
+
+	*backup = *foundBackups[0]
+
+	return nil
+}
+
+// Returns a signed download URL for one of a game's backups
+func GetBackupDownload(download *gamev1Resource.BackupDownload, userId uuid.UUID, name string, backupName string) error {
+	foundGame, err := findGame(userId, name)
+	if err != nil {
+		return err
+	}
+
+	if _, ok := agones.ParseBackupTime(foundGame.GetResourceName(), backupName); !ok {
+		return ErrBackupNotFound
+	}
+
+	url, err := storage.Get().SignedURL(backupName, BackupDownloadExpiry)
+	if err != nil {
+		if err == storage.ErrObjectNotFound {
+			return ErrBackupNotFound
+		}
+		return err
+	}
+
+	download.URL = url
+	download.ExpiresAt = time.Now().Add(BackupDownloadExpiry)
+
+	return nil
+}
+
+// Restores one of a game's backups the next time the game is started
+func RestoreBackup(game *gamev1Resource.Game, userId uuid.UUID, name string, backupName string) error {
+	foundGame, err := findGame(userId, name)
+	if err != nil {
+		return err
+	}
+
+	foundBackups, err := getBackups(foundGame)
+	if err != nil {
+		return err
+	}
+
+	found := false
+	for _, foundBackup := range foundBackups {
+		if foundBackup.Name == backupName {
+			found = true
+			break
+		}
+	}
+
+	if !found {
+		return ErrBackupNotFound
+	}
+
+	if err := db.DB().RunInTransaction(context.Background(), func(tx *pg.Tx) error {
+		// the game may have been deleted while its backups were listed
+		if err := getByNameAndUserId(tx, foundGame, name, userId); err != nil {
+			if err == pg.ErrNoRows {
+				return ErrGameServerNotFound
+			}
+			return err
+		}
+
+		return setRestoreBackup(tx, foundGame, backupName)
+	}); err != nil {
+		return err
+	}
+
+	gs, err := agones.Client().GetForUser(foundGame.GetResourceName(), userId)
+	if err != nil && !k8sErrors.IsNotFound(err) {
+		return err
+	}
+
+	game.MergeGame(foundGame, gs)
+
+	return nil
+}
+
+// Gets one of a user's games. Storage, RCON and exec calls are made after the game is loaded
+// so that transactions are not held open during them
+func findGame(userId uuid.UUID, name string) (*gamev1Model.Game, error) {
+	var foundGame gamev1Model.Game
+
+	if err := db.DB().RunInTransaction(context.Background(), func(tx *pg.Tx) error {
+		if err := getByNameAndUserId(tx, &foundGame, name, userId); err != nil {
+			if err == pg.ErrNoRows {
+				return ErrGameServerNotFound
+			}
+			return err
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	return &foundGame, nil
+}
+
+// Returns a game's backups from newest to oldest
+func getBackups(game *gamev1Model.Game) ([]*gamev1Resource.Backup, error) {
+	objects, err := storage.Get().List(agones.BackupPrefix(game.GetResourceName()))
+	if err != nil {
+		return nil, err
+	}
+
+	backups := []*gamev1Resource.Backup{}
+
+	for _, object := range objects {
+		// names of games prefixed with this game's name share its prefix
+		createdAt, ok := agones.ParseBackupTime(game.GetResourceName(), object.Name)
+		if !ok {
+			continue
+		}
+
+		backup := gamev1Resource.Backup{}
+		backup.MergeObject(object, createdAt)
+		backups = append(backups, &backup)
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].CreatedAt.After(backups[j].CreatedAt)
+	})
+
+	return backups, nil
+}
@@ -0,0 +1,191 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	agonesv1 "agones.dev/agones/pkg/apis/agones/v1"
+	"github.com/go-pg/pg/v10"
+	"github.com/google/uuid"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"agones-minecraft/config"
+	"agones-minecraft/db"
+	gamev1Model "agones-minecraft/models/v1/game"
+	userv1Model "agones-minecraft/models/v1/user"
+	"agones-minecraft/services/k8s/agones"
+	"agones-minecraft/services/rcon"
+)
+
+var (
+	ErrConsoleUnsupported error = errors.New("console is only supported for java edition games")
+	ErrGameNotOnline      error = errors.New("game server is not online")
+	ErrCommandNotAllowed  error = errors.New("command not allowed")
+)
+
+// RCON session to a game limited by its user's allowed and denied commands
+type Console struct {
+	client *rcon.Client
+	allow  []string
+	deny   []string
+}
+
+// Runs a command if it is allowed for the console's user
+func (c *Console) Command(command string) (string, error) {
+	if !c.allows(command) {
+		return "", ErrCommandNotAllowed
+	}
+	return c.client.Command(command)
+}
+
+func (c *Console) Close() error {
+	return c.client.Close()
+}
+
+// Commands are matched on their name without a leading slash or namespace. e.g. "/minecraft:say hi" matches "say".
+// Commands run by execute must be allowed as well
+func (c *Console) allows(command string) bool {
+	names := commandNames(command)
+	if len(names) == 0 {
+		return false
+	}
+
+	for _, name := range names {
+		if !c.allowsName(name) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (c *Console) allowsName(name string) bool {
+	for _, denied := range c.deny {
+		if strings.EqualFold(name, denied) {
+			return false
+		}
+	}
+
+	if len(c.allow) == 0 {
+		return true
+	}
+
+	for _, allowed := range c.allow {
+		if strings.EqualFold(name, allowed) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// Returns the names of a command and the commands it runs through execute. Every word after a "run" of an
+// execute is treated as a command since arguments such as quoted selectors can contain "run" themselves
+func commandNames(command string) []string {
+	fields := strings.Fields(command)
+	if len(fields) == 0 {
+		return nil
+	}
+
+	names := []string{commandName(fields[0])}
+	if names[0] != "execute" {
+		return names
+	}
+
+	for i := 1; i < len(fields); i++ {
+		if !strings.EqualFold(fields[i], "run") {
+			continue
+		}
+		// execute without a command to run is incomplete and runs nothing
+		if i+1 < len(fields) {
+			names = append(names, commandName(fields[i+1]))
+		}
+	}
+
+	return names
+}
+
+func commandName(field string) string {
+	name := strings.ToLower(strings.TrimPrefix(field, "/"))
+	if i := strings.Index(name, ":"); i >= 0 {
+		name = name[i+1:]
+	}
+	return name
+}
+
+// Opens a console to an online game. The console must be closed by the caller
+func OpenConsole(userId uuid.UUID, name string) (*Console, error) {
+	var foundUser userv1Model.User
+	var gs *agonesv1.GameServer
+
+	if err := db.DB().RunInTransaction(context.Background(), func(tx *pg.Tx) error {
+		var foundGame gamev1Model.Game
+		if err := getByNameAndUserId(tx, &foundGame, name, userId); err != nil {
+			if err == pg.ErrNoRows {
+				return ErrGameServerNotFound
+			}
+			return err
+		}
+
+		if foundGame.Edition != gamev1Model.JavaEdition {
+			return ErrConsoleUnsupported
+		}
+
+		if err := tx.Model(&foundUser).Where("u.id = ?", userId).Select(); err != nil {
+			return err
+		}
+
+		var err error
+		gs, err = agones.Client().GetForUser(foundGame.GetResourceName(), userId)
+		if err != nil {
+			if k8sErrors.IsNotFound(err) {
+				return ErrGameNotOnline
+			}
+			return err
+		}
+
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	if !agones.IsOnline(gs) {
+		return nil, ErrGameNotOnline
+	}
+
+	// dialed after the transaction so that it is not held open while connecting
+	client, err := agones.Client().RCON(gs)
+	if err != nil {
+		return nil, err
+	}
+
+	allow := foundUser.ConsoleAllow
+	if len(allow) == 0 {
+		allow = config.GetConsoleAllow()
+	}
+
+	return &Console{
+		client: client,
+		allow:  allow,
+		deny:   append(config.GetConsoleDeny(), foundUser.ConsoleDeny...),
+	}, nil
+}
+
+// Runs a single command on an online game
+func RunCommand(output *string, userId uuid.UUID, name string, command string) error {
+	console, err := OpenConsole(userId, name)
+	if err != nil {
+		return err
+	}
+	defer console.Close()
+
+	res, err := console.Command(command)
+	if err != nil {
+		return err
+	}
+
+	*output = res
+
+	return nil
+}
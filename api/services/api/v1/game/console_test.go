@@ -0,0 +1,67 @@
+package game
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCommandNames(t *testing.T) {
+	tests := []struct {
+		command string
+		want    []string
+	}{
+		{"say hi", []string{"say"}},
+		{"/say hi", []string{"say"}},
+		{"STOP", []string{"stop"}},
+		{"minecraft:stop", []string{"stop"}},
+		{"/minecraft:stop", []string{"stop"}},
+		{"execute as @a run say hi", []string{"execute", "say"}},
+		{"execute run stop", []string{"execute", "stop"}},
+		{"execute as @a run minecraft:stop", []string{"execute", "stop"}},
+		{"execute run execute run stop", []string{"execute", "execute", "stop"}},
+		{`execute if entity @e[name="x run y"] run stop`, []string{"execute", `y"]`, "stop"}},
+		{"execute as @a", []string{"execute"}},
+		{"execute run", []string{"execute"}},
+		{"say run stop", []string{"say"}},
+		{"   ", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.command, func(t *testing.T) {
+			if got := commandNames(tt.command); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("commandNames() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestConsoleAllows(t *testing.T) {
+	tests := []struct {
+		name    string
+		allow   []string
+		deny    []string
+		command string
+		want    bool
+	}{
+		{"allowed", []string{"say"}, nil, "say hi", true},
+		{"not allowed", []string{"say"}, nil, "give @p diamond", false},
+		{"denied", nil, []string{"stop"}, "stop", false},
+		{"denied with slash", nil, []string{"stop"}, "/stop", false},
+		{"denied with namespace", nil, []string{"stop"}, "minecraft:stop", false},
+		{"denied through execute", []string{"execute", "say"}, []string{"stop"}, "execute run stop", false},
+		{"not allowed through execute", []string{"execute", "say"}, nil, "execute as @a run give @p diamond", false},
+		{"allowed through execute", []string{"execute", "say"}, nil, "execute as @a run say hi", true},
+		{"denied wins over allowed", []string{"stop"}, []string{"stop"}, "stop", false},
+		{"everything allowed without allow list", nil, []string{"stop"}, "give @p diamond", true},
+		{"empty", nil, nil, "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			console := Console{allow: tt.allow, deny: tt.deny}
+			if got := console.allows(tt.command); got != tt.want {
+				t.Errorf("allows(%q) = %v, want %v", tt.command, got, tt.want)
+			}
+		})
+	}
+}
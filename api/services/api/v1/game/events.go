@@ -0,0 +1,39 @@
+package game
+
+import (
+	"github.com/google/uuid"
+
+	gamev1Resource "agones-minecraft/resources/api/v1/game"
+	"agones-minecraft/services/k8s/agones"
+)
+
+// Subscribes to changes of a user's games. Events are sent until the returned func is called
+func SubscribeGameEvents(userId uuid.UUID) (<-chan *gamev1Resource.GameEvent, func()) {
+	gsEvents, unsubscribe := agones.Client().Subscribe(userId)
+	events := make(chan *gamev1Resource.GameEvent)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(events)
+		for e := range gsEvents {
+			// GameServers without a game UUID were not created by the API
+			if _, err := uuid.Parse(e.GameServer.Labels[agones.UUIDLabel]); err != nil {
+				continue
+			}
+
+			event := gamev1Resource.GameEvent{}
+			event.MergeEvent(e)
+
+			select {
+			case events <- &event:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	return events, func() {
+		close(done)
+		unsubscribe()
+	}
+}
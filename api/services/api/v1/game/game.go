@@ -1,27 +1,36 @@
 package game
 
 import (
+	"agones-minecraft/config"
 	"agones-minecraft/db"
 	"context"
 	"errors"
-	"fmt"
+	"sync"
 	"time"
 
 	agonesv1 "agones.dev/agones/pkg/apis/agones/v1"
 	"github.com/go-pg/pg/v10"
 	"github.com/google/uuid"
+	"go.uber.org/zap"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	gamev1Model "agones-minecraft/models/v1/game"
 	"agones-minecraft/models/v1/model"
 	gamev1Resource "agones-minecraft/resources/api/v1/game"
+	"agones-minecraft/services/catalog"
+	"agones-minecraft/services/k8s"
 	"agones-minecraft/services/k8s/agones"
+	"agones-minecraft/services/ping"
 )
 
 var (
 	ErrSubdomainTaken      error = errors.New("subdomain not available")
 	ErrGameServerNameTaken error = errors.New("game server name not available")
 	ErrGameServerNotFound  error = errors.New("game server not found")
+	ErrGameAlreadyStopped  error = errors.New("game server is already stopped")
+	ErrGameAlreadyStarted  error = errors.New("game server is already started")
+	ErrGameNotStopped      error = errors.New("game server must be stopped to change its tier")
 )
 
 type ErrDeletingGameFromK8S struct {
@@ -32,6 +41,15 @@ type ErrDeletingGameFromDB struct {
 	error
 }
 
+type ErrBackingUpGame struct {
+	error
+}
+
+// Server type not available in the catalog for the game's edition and version
+type ErrInvalidServerType struct {
+	error
+}
+
 func GetGameById(game *gamev1Resource.Game, id uuid.UUID) error {
 	return db.DB().RunInTransaction(context.Background(), func(tx *pg.Tx) error {
 		var foundGame gamev1Model.Game
@@ -55,7 +73,9 @@ func GetGameById(game *gamev1Resource.Game, id uuid.UUID) error {
 }
 
 func GetGameByNameAndUserId(game *gamev1Resource.Game, name string, userId uuid.UUID) error {
-	return db.DB().RunInTransaction(context.Background(), func(tx *pg.Tx) error {
+	var gs *agonesv1.GameServer
+
+	if err := db.DB().RunInTransaction(context.Background(), func(tx *pg.Tx) error {
 		var foundGame gamev1Model.Game
 
 		if err := getByNameAndUserId(tx, &foundGame, name, userId); err != nil {
@@ -65,11 +85,13 @@ func GetGameByNameAndUserId(game *gamev1Resource.Game, name string, userId uuid.
 			return err
 		}
 
-		gs, err := agones.Client().GetForUser(foundGame.GetResourceName(), userId)
+		var err error
+		gs, err = agones.Client().GetForUser(foundGame.GetResourceName(), userId)
 		if err != nil {
 			if !k8sErrors.IsNotFound(err) {
 				return err
 			}
+			gs = nil
 		}
 
 		if err := reconcileGameState(tx, &foundGame, gs); err != nil {
@@ -77,13 +99,23 @@ func GetGameByNameAndUserId(game *gamev1Resource.Game, name string, userId uuid.
 		}
 
 		game.MergeGame(&foundGame, gs)
+
 		return nil
-	})
+	}); err != nil {
+		return err
+	}
+
+	// pinged after the transaction commits so that unresponsive servers do not hold it open
+	setServerStatus(game, gs)
+
+	return nil
 }
 
 func ListGamesForUser(games *[]*gamev1Resource.Game, userId uuid.UUID) error {
-	return db.DB().RunInTransaction(context.Background(), func(tx *pg.Tx) error {
-		foundGames := []*gamev1Model.Game{}
+	foundGames := []*gamev1Model.Game{}
+	listedGames := make(map[string]*agonesv1.GameServer)
+
+	if err := db.DB().RunInTransaction(context.Background(), func(tx *pg.Tx) error {
 		err := tx.Model(&foundGames).Where("user_id = ?", userId).Select()
 		if err != nil {
 			if err != pg.ErrNoRows {
@@ -100,47 +132,68 @@ func ListGamesForUser(games *[]*gamev1Resource.Game, userId uuid.UUID) error {
 			return err
 		}
 
-		listedGames := make(map[string]*agonesv1.GameServer)
 		for _, gs := range gsList {
 			listedGames[agones.GetUUID(gs).String()] = gs
 		}
 
-		for _, foundGame := range foundGames {
-			game := gamev1Resource.Game{}
-			fmt.Println(listedGames)
-			listedGame := listedGames[foundGame.ID.String()]
-			game.MergeGame(foundGame, listedGame)
-			*games = append(*games, &game)
-		}
-
 		return nil
-	})
+	}); err != nil {
+		return err
+	}
+
+	// servers are pinged after the transaction commits and concurrently so that unresponsive servers do not add up
+	var wg sync.WaitGroup
+
+	for _, foundGame := range foundGames {
+		game := gamev1Resource.Game{}
+		listedGame := listedGames[foundGame.ID.String()]
+		game.MergeGame(foundGame, listedGame)
+		*games = append(*games, &game)
+
+		wg.Add(1)
+		go func(game *gamev1Resource.Game, gs *agonesv1.GameServer) {
+			defer wg.Done()
+			setServerStatus(game, gs)
+		}(&game, listedGame)
+	}
+
+	wg.Wait()
+
+	return nil
 }
 
 func CreateGame(game *gamev1Resource.Game, edition gamev1Model.Edition, body gamev1Resource.CreateGameBody, userId uuid.UUID) error {
-	return db.DB().RunInTransaction(context.Background(), func(tx *pg.Tx) error {
-		var gs *agonesv1.GameServer
+	backupStorage, err := getBackupUsage(userId)
+	if err != nil {
+		return err
+	}
 
-		var builder agones.MCServerBuilder
+	if err := db.DB().RunInTransaction(context.Background(), func(tx *pg.Tx) error {
+		uuid := uuid.New()
 
-		switch edition {
-		case gamev1Model.Edition(agones.JavaEdition):
-			builder = agones.NewJavaServerBuilder()
-		case gamev1Model.Edition(agones.BedrockEdition):
-			builder = agones.NewBedrockServerBuilder()
-		}
+		settings := gamev1Model.DefaultSettings()
+		settings.Tier = config.DefaultServerTier
+		body.GameSettingsBody.Apply(&settings)
 
-		uuid := uuid.New()
+		serverType := body.Type
+		if serverType == "" {
+			serverType = gamev1Model.Vanilla
+		}
 
-		gs = agones.NewDirector(builder).BuildServer(body.Name, body.Subdomain, uuid, userId)
+		if err := catalog.Validate(serverType, edition, settings.Version); err != nil {
+			return &ErrInvalidServerType{err}
+		}
 
 		gameModel := gamev1Model.Game{
-			Model:   model.Model{ID: uuid},
-			Name:    agones.GetName(gs),
-			State:   gamev1Model.On,
-			Address: agones.GetAddress(gs),
-			UserID:  userId,
-			Edition: gamev1Model.JavaEdition,
+			Model:      model.Model{ID: uuid},
+			Name:       body.Name,
+			State:      gamev1Model.On,
+			Address:    agones.NewAddress(body.Subdomain),
+			UserID:     userId,
+			Edition:    edition,
+			Type:       serverType,
+			Settings:   settings,
+			Persistent: body.Persistent,
 		}
 
 		ok, err := addressIsTaken(tx, &gameModel)
@@ -157,23 +210,59 @@ func CreateGame(game *gamev1Resource.Game, edition gamev1Model.Edition, body gam
 			return ErrGameServerNameTaken
 		}
 
+		if err := checkCreateQuota(tx, &gameModel, backupStorage); err != nil {
+			return err
+		}
+
 		if _, err := tx.Model(&gameModel).Insert(); err != nil {
 			return err
 		}
 
-		newGs, err := agones.Client().Create(gs)
+		builder := newBuilder(&gameModel)
+
+		// owners are operators on their own servers
+		if edition == gamev1Model.JavaEdition {
+			ops, err := ensureOwnerOp(tx, &gameModel)
+			if err != nil {
+				return err
+			}
+			builder.SetOps(ops)
+		}
+
+		gs := agones.NewDirector(builder).BuildServer(body.Name, body.Subdomain, uuid, userId)
+
+		// the GameServer is created by the outbox worker once the game is committed
+		if err := enqueueOutboxMessage(tx, &gamev1Model.OutboxMessage{
+			Type:         gamev1Model.CreateGameServer,
+			GameID:       gameModel.ID,
+			ResourceName: gameModel.GetResourceName(),
+			GameServer:   gs,
+			Persistent:   gameModel.Persistent,
+		}); err != nil {
+			return err
+		}
+
+		operation, err := newOperation(tx, &gameModel, gamev1Model.CreateOperation)
 		if err != nil {
 			return err
 		}
 
-		game.MergeGame(&gameModel, newGs)
+		game.MergeGame(&gameModel, nil)
+		game.Operation = &gamev1Resource.Operation{}
+		game.Operation.MergeOperation(operation)
 
 		return nil
-	})
+	}); err != nil {
+		return err
+	}
+
+	notifyOutbox()
+
+	return nil
 }
 
-func DeleteGame(userId uuid.UUID, name string) error {
-	return db.DB().RunInTransaction(context.Background(), func(tx *pg.Tx) error {
+func DeleteGame(operation *gamev1Resource.Operation, userId uuid.UUID, name string) error {
+	if err := db.DB().RunInTransaction(context.Background(), func(tx *pg.Tx) error {
 		var foundGame gamev1Model.Game
 		if err := getByNameAndUserId(tx, &foundGame, name, userId); err != nil {
 			if err == pg.ErrNoRows {
@@ -186,10 +275,246 @@ func DeleteGame(userId uuid.UUID, name string) error {
 			return &ErrDeletingGameFromDB{err}
 		}
 
-		if err := agones.Client().Delete(foundGame.GetResourceName()); err != nil {
-			return &ErrDeletingGameFromK8S{err}
+		// the GameServer and world are deleted by the outbox worker once the game is committed
+		if err := enqueueOutboxMessage(tx, &gamev1Model.OutboxMessage{
+			Type:         gamev1Model.DeleteGameServer,
+			GameID:       foundGame.ID,
+			ResourceName: foundGame.GetResourceName(),
+			Persistent:   foundGame.Persistent,
+		}); err != nil {
+			return &ErrDeletingGameFromDB{err}
+		}
+
+		deleteOperation, err := newOperation(tx, &foundGame, gamev1Model.DeleteOperation)
+		if err != nil {
+			return &ErrDeletingGameFromDB{err}
+		}
+
+		operation.MergeOperation(deleteOperation)
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	notifyOutbox()
+
+	return nil
+}
+
+// Marks the game as Off. The game's world is backed up and its GameServer deleted by the outbox worker
+// once the game is committed. The game's world is restored from the backup when the game is started again
+func StopGame(game *gamev1Resource.Game, userId uuid.UUID, name string) error {
+	if err := db.DB().RunInTransaction(context.Background(), func(tx *pg.Tx) error {
+		var foundGame gamev1Model.Game
+		if err := getByNameAndUserId(tx, &foundGame, name, userId); err != nil {
+			if err == pg.ErrNoRows {
+				return ErrGameServerNotFound
+			}
+			return err
+		}
+
+		gs, err := agones.Client().GetForUser(foundGame.GetResourceName(), userId)
+		if err != nil {
+			if !k8sErrors.IsNotFound(err) {
+				return err
+			}
+			gs = nil
+		}
+
+		pending, err := pendingOutboxGames(tx, foundGame.ID)
+		if err != nil {
+			return err
+		}
+
+		// GameServers of stopped games are only left while their stop is pending
+		if foundGame.State == gamev1Model.Off && (gs == nil || pending[foundGame.ID]) {
+			return ErrGameAlreadyStopped
+		}
+
+		if err := enqueueOutboxMessage(tx, &gamev1Model.OutboxMessage{
+			Type:         gamev1Model.StopGameServer,
+			GameID:       foundGame.ID,
+			ResourceName: foundGame.GetResourceName(),
+		}); err != nil {
+			return err
+		}
+
+		if err := setGameState(tx, &foundGame, gamev1Model.Off); err != nil {
+			return err
+		}
+
+		operation, err := newOperation(tx, &foundGame, gamev1Model.StopOperation)
+		if err != nil {
+			return err
+		}
+
+		game.MergeGame(&foundGame, nil)
+		game.Operation = &gamev1Resource.Operation{}
+		game.Operation.MergeOperation(operation)
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	notifyOutbox()
+
+	return nil
+}
+
+// Marks a stopped game as On. Its GameServer is recreated with its latest world backup by the outbox worker
+// once the game is committed
+func StartGame(game *gamev1Resource.Game, userId uuid.UUID, name string) error {
+	backupStorage, err := getBackupUsage(userId)
+	if err != nil {
+		return err
+	}
+
+	if err := db.DB().RunInTransaction(context.Background(), func(tx *pg.Tx) error {
+		var foundGame gamev1Model.Game
+		if err := getByNameAndUserId(tx, &foundGame, name, userId); err != nil {
+			if err == pg.ErrNoRows {
+				return ErrGameServerNotFound
+			}
+			return err
+		}
+
+		gs, err := agones.Client().GetForUser(foundGame.GetResourceName(), userId)
+		if err != nil {
+			if !k8sErrors.IsNotFound(err) {
+				return err
+			}
+			gs = nil
+		}
+
+		pending, err := pendingOutboxGames(tx, foundGame.ID)
+		if err != nil {
+			return err
+		}
+
+		// started games without a GameServer are only missing one while their create or start is pending
+		if foundGame.State == gamev1Model.On && (gs != nil || pending[foundGame.ID]) {
+			return ErrGameAlreadyStarted
+		}
+
+		if err := checkStartQuota(tx, &foundGame, backupStorage); err != nil {
+			return err
+		}
+
+		subdomain := agones.GetSubdomainFromAddress(foundGame.Address)
+
+		foundMods, err := getGameMods(tx, foundGame.ID)
+		if err != nil {
+			return err
+		}
+
+		builder := newBuilder(&foundGame)
+		builder.SetMods(foundMods)
+
+		if foundGame.Edition == gamev1Model.JavaEdition {
+			whitelist, err := getGamePlayers(tx, gamev1Model.Whitelist, foundGame.ID)
+			if err != nil {
+				return err
+			}
+
+			ops, err := ensureOwnerOp(tx, &foundGame)
+			if err != nil {
+				return err
+			}
+
+			builder.SetWhitelist(whitelist)
+			builder.SetOps(ops)
+		}
+
+		director := agones.NewDirector(builder)
+
+		var newGs *agonesv1.GameServer
+
+		// persistent worlds are kept on their volume claim and only load a backup being restored
+		if foundGame.RestoreBackup != "" {
+			newGs = director.BuildServerWithBackup(foundGame.Name, subdomain, foundGame.ID, userId, foundGame.RestoreBackup)
+		} else if foundGame.Persistent {
+			newGs = director.BuildServer(foundGame.Name, subdomain, foundGame.ID, userId)
+		} else {
+			newGs = director.BuildServerWithBackup(foundGame.Name, subdomain, foundGame.ID, userId, "")
+		}
+
+		// applied after any pending stop so the new GameServer is created once the old one is deleted
+		if err := enqueueOutboxMessage(tx, &gamev1Model.OutboxMessage{
+			Type:         gamev1Model.StartGameServer,
+			GameID:       foundGame.ID,
+			ResourceName: foundGame.GetResourceName(),
+			GameServer:   newGs,
+			Persistent:   foundGame.Persistent,
+		}); err != nil {
+			return err
+		}
+
+		if err := setGameState(tx, &foundGame, gamev1Model.On); err != nil {
+			return err
+		}
+
+		// restores only apply to the next start
+		if foundGame.RestoreBackup != "" {
+			if err := setRestoreBackup(tx, &foundGame, ""); err != nil {
+				return err
+			}
 		}
 
+		operation, err := newOperation(tx, &foundGame, gamev1Model.StartOperation)
+		if err != nil {
+			return err
+		}
+
+		game.MergeGame(&foundGame, nil)
+		game.Operation = &gamev1Resource.Operation{}
+		game.Operation.MergeOperation(operation)
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	notifyOutbox()
+
+	return nil
+}
+
+// Updates a game's settings. GameServers are immutable so settings are applied the next time the game is started
+func UpdateGameSettings(game *gamev1Resource.Game, userId uuid.UUID, name string, body gamev1Resource.GameSettingsBody) error {
+	return db.DB().RunInTransaction(context.Background(), func(tx *pg.Tx) error {
+		var foundGame gamev1Model.Game
+		if err := getByNameAndUserId(tx, &foundGame, name, userId); err != nil {
+			if err == pg.ErrNoRows {
+				return ErrGameServerNotFound
+			}
+			return err
+		}
+
+		// resources of running servers cannot change
+		if body.Tier != nil && *body.Tier != foundGame.Tier && foundGame.State != gamev1Model.Off {
+			return ErrGameNotStopped
+		}
+
+		body.Apply(&foundGame.Settings)
+		foundGame.UpdatedAt = time.Now()
+
+		if _, err := tx.Model(&foundGame).
+			Column("motd", "slots", "difficulty", "game_mode", "version", "seed", "level_type", "whitelist", "idle_timeout", "tier", "updated_at").
+			Column("backup_enabled", "backup_cron", "backup_keep_last", "backup_keep_daily", "backup_keep_weekly").
+			WherePK().
+			Update(); err != nil {
+			return err
+		}
+
+		gs, err := agones.Client().GetForUser(foundGame.GetResourceName(), userId)
+		if err != nil && !k8sErrors.IsNotFound(err) {
+			return err
+		}
+
+		game.MergeGame(&foundGame, gs)
+
 		return nil
 	})
 }
@@ -201,8 +526,13 @@ func UpdateGame(game *gamev1Model.Game) error {
 
 // Reconcile game state in database to match the state in cluster
 func reconcileGameState(tx *pg.Tx, game *gamev1Model.Game, gs *agonesv1.GameServer) error {
+	// GameServers of games with pending outbox messages are still being created or deleted
+	pending, err := pendingOutboxGames(tx, game.ID)
+	if err != nil || pending[game.ID] {
+		return err
+	}
+
 	realState := agones.GetState(gs)
-	var err error
 	if realState != game.State {
 		_, err = tx.Model(game).
 			Set("state = ?", realState).
@@ -223,10 +553,30 @@ func reconcileGameListStates(tx *pg.Tx, games []*gamev1Model.Game, gsList []*ago
 		realStates[agones.GetUUID(gs).String()] = agones.GetState(gs)
 	}
 
+	ids := make([]uuid.UUID, 0, len(games))
 	for _, game := range games {
-		if game.State != realStates[game.ID.String()] {
-			game.State = realStates[game.ID.String()]
+		ids = append(ids, game.ID)
+	}
+
+	pending, err := pendingOutboxGames(tx, ids...)
+	if err != nil {
+		return err
+	}
+
+	for _, game := range games {
+		if pending[game.ID] {
+			continue
+		}
+
+		realState, ok := realStates[game.ID.String()]
+		if !ok {
+			realState = gamev1Model.Off
+		}
+
+		if game.State != realState {
+			game.State = realState
 			game.UpdatedAt = now
+			updates = append(updates, game)
 		}
 	}
 
@@ -238,6 +588,106 @@ func reconcileGameListStates(tx *pg.Tx, games []*gamev1Model.Game, gsList []*ago
 	return nil
 }
 
+func setRestoreBackup(tx *pg.Tx, game *gamev1Model.Game, backup string) error {
+	game.RestoreBackup = backup
+	game.UpdatedAt = time.Now()
+
+	_, err := tx.Model(game).Column("restore_backup", "updated_at").WherePK().Update()
+	return err
+}
+
+func setGameState(tx *pg.Tx, game *gamev1Model.Game, state gamev1Model.GameState) error {
+	game.State = state
+	game.UpdatedAt = time.Now()
+
+	_, err := tx.Model(game).Column("state", "updated_at").WherePK().Update()
+	return err
+}
+
+// Creates the persistent world volume claim for a game. Existing claims are reused
+func createWorldVolumeClaim(game *gamev1Model.Game) error {
+	claim, err := agones.NewWorldVolumeClaim(game.ID, game.UserID)
+	if err != nil {
+		return err
+	}
+
+	if _, err := k8s.GetClient().CreateVolumeClaim(claim); err != nil && !k8sErrors.IsAlreadyExists(err) {
+		return err
+	}
+
+	return nil
+}
+
+func deleteWorldVolumeClaim(game *gamev1Model.Game) error {
+	err := k8s.GetClient().DeleteVolumeClaim(metav1.NamespaceDefault, agones.WorldVolumeClaimName(game.ID))
+	if err != nil && !k8sErrors.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
+// Sets the live status of an online game's server. Servers that do not answer pings are left without a status
+func setServerStatus(game *gamev1Resource.Game, gs *agonesv1.GameServer) {
+	if gs == nil || !agones.IsOnline(gs) {
+		return
+	}
+
+	status, err := pingServer(game.Edition, gs)
+	if err != nil {
+		zap.L().Debug("error pinging game server", zap.String("game", game.Name), zap.Error(err))
+		return
+	}
+
+	game.MergeServerStatus(status)
+}
+
+// Pings an online game's server. Results are cached briefly
+func pingServer(edition gamev1Model.Edition, gs *agonesv1.GameServer) (*ping.Status, error) {
+	address := agones.GetServerAddress(gs)
+	if address == "" {
+		return nil, ErrGameNotOnline
+	}
+
+	if edition == gamev1Model.BedrockEdition {
+		return ping.Bedrock(address)
+	}
+
+	return ping.Java(address)
+}
+
+// Creates a GameServer and its RCON password Secret. The GameServer is deleted if its Secret cannot be created
+func createServer(gs *agonesv1.GameServer) (*agonesv1.GameServer, error) {
+	newGs, err := agones.Client().Create(gs)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := agones.Client().CreateRCONSecret(newGs); err != nil {
+		agones.Client().Delete(newGs.Name)
+		return nil, err
+	}
+
+	return newGs, nil
+}
+
+// Returns a server builder for the game's edition, settings and world volume
+func newBuilder(game *gamev1Model.Game) agones.MCServerBuilder {
+	var builder agones.MCServerBuilder = agones.NewJavaServerBuilder()
+	if game.Edition == gamev1Model.BedrockEdition {
+		builder = agones.NewBedrockServerBuilder()
+	}
+
+	builder.SetSettings(game.Settings)
+	builder.SetServerType(game.Type)
+
+	if game.Persistent {
+		builder.SetWorldVolumeClaim(agones.WorldVolumeClaimName(game.ID))
+	}
+
+	return builder
+}
+
 func getByNameAndUserId(tx *pg.Tx, game *gamev1Model.Game, name string, userId uuid.UUID) error {
 	return tx.Model(game).
 		Where("name = ?", name).
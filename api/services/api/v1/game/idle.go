@@ -0,0 +1,129 @@
+package game
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"agones-minecraft/db"
+	gamev1Model "agones-minecraft/models/v1/game"
+	gamev1Resource "agones-minecraft/resources/api/v1/game"
+	"agones-minecraft/services/k8s/agones"
+)
+
+// When each online game was first seen without players. Only accessed by the idle shutdown worker.
+// Idle time is not persisted so games get a full idle timeout after the API restarts
+var emptySince map[uuid.UUID]time.Time = map[uuid.UUID]time.Time{}
+
+// Arbitrary key of the Postgres advisory lock held by the API replica stopping idle games
+const idleLockKey int64 = 4046
+
+// Backs up and stops online games that have had no players for longer than their idle timeout.
+// Games that fail are logged and skipped
+func StopIdleGames() error {
+	return db.DB().RunInTransaction(context.Background(), func(tx *pg.Tx) error {
+		// released when the transaction ends
+		var locked bool
+		if _, err := tx.QueryOne(pg.Scan(&locked), "SELECT pg_try_advisory_xact_lock(?)", idleLockKey); err != nil {
+			return err
+		}
+
+		// another replica is stopping idle games. Its idle times are not known here so tracked games start over
+		if !locked {
+			emptySince = map[uuid.UUID]time.Time{}
+			return nil
+		}
+
+		foundGames := []*gamev1Model.Game{}
+		if err := tx.Model(&foundGames).
+			Where("state = ?", gamev1Model.On).
+			Where("idle_timeout > 0").
+			Select(); err != nil && err != pg.ErrNoRows {
+			return err
+		}
+
+		checked := map[uuid.UUID]bool{}
+		now := time.Now()
+
+		for _, foundGame := range foundGames {
+			checked[foundGame.ID] = true
+			if err := stopGameIfIdle(foundGame, now); err != nil {
+				zap.L().Warn("error stopping idle game", zap.String("game", foundGame.GetResourceName()), zap.Error(err))
+			}
+		}
+
+		// forget games that were stopped, deleted or had their idle timeout disabled
+		for id := range emptySince {
+			if !checked[id] {
+				delete(emptySince, id)
+			}
+		}
+
+		return nil
+	})
+}
+
+func stopGameIfIdle(game *gamev1Model.Game, now time.Time) error {
+	gs, err := agones.Client().GetForUser(game.GetResourceName(), game.UserID)
+	if err != nil {
+		if k8sErrors.IsNotFound(err) {
+			delete(emptySince, game.ID)
+			return nil
+		}
+		return err
+	}
+
+	// starting servers have no players yet
+	if !agones.IsOnline(gs) {
+		delete(emptySince, game.ID)
+		return nil
+	}
+
+	// servers that do not answer pings are not counted as idle
+	status, err := pingServer(game.Edition, gs)
+	if err != nil {
+		return err
+	}
+
+	if status.PlayersOnline > 0 {
+		delete(emptySince, game.ID)
+		return nil
+	}
+
+	since, ok := emptySince[game.ID]
+	if !ok {
+		emptySince[game.ID] = now
+		return nil
+	}
+
+	timeout := time.Duration(game.IdleTimeout) * time.Minute
+	if now.Sub(since) < timeout {
+		return nil
+	}
+
+	var stopped gamev1Resource.Game
+	if err := StopGame(&stopped, game.UserID, game.Name); err != nil {
+		if err == ErrGameAlreadyStopped {
+			delete(emptySince, game.ID)
+			return nil
+		}
+		return err
+	}
+
+	delete(emptySince, game.ID)
+
+	message := fmt.Sprintf("Stopped after %s with no players online", timeout)
+	if err := agones.Client().RecordEvent(gs, corev1.EventTypeNormal, agones.IdleShutdownReason, message); err != nil {
+		zap.L().Warn("error recording idle shutdown event", zap.String("game", game.GetResourceName()), zap.Error(err))
+	}
+
+	zap.L().Info("stopped idle game", zap.String("game", game.GetResourceName()), zap.Duration("timeout", timeout))
+
+	return nil
+}
@@ -0,0 +1,134 @@
+package game
+
+import (
+	"context"
+	"errors"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/google/uuid"
+
+	"agones-minecraft/db"
+	gamev1Model "agones-minecraft/models/v1/game"
+	gamev1Resource "agones-minecraft/resources/api/v1/game"
+	"agones-minecraft/services/mods"
+)
+
+var (
+	ErrModAlreadyAdded error = errors.New("mod already added to game")
+	ErrGameModNotFound error = errors.New("mod not found")
+)
+
+// Mod could not be resolved for the game's server type and version
+type ErrResolvingMod struct {
+	error
+}
+
+func (e *ErrResolvingMod) Unwrap() error {
+	return e.error
+}
+
+func ListGameMods(gameMods *[]*gamev1Resource.GameMod, userId uuid.UUID, name string) error {
+	return db.DB().RunInTransaction(context.Background(), func(tx *pg.Tx) error {
+		var foundGame gamev1Model.Game
+		if err := getByNameAndUserId(tx, &foundGame, name, userId); err != nil {
+			if err == pg.ErrNoRows {
+				return ErrGameServerNotFound
+			}
+			return err
+		}
+
+		foundMods, err := getGameMods(tx, foundGame.ID)
+		if err != nil {
+			return err
+		}
+
+		for _, foundMod := range foundMods {
+			gameMod := gamev1Resource.GameMod{}
+			gameMod.MergeGameMod(foundMod)
+			*gameMods = append(*gameMods, &gameMod)
+		}
+
+		return nil
+	})
+}
+
+// Resolves and adds a mod or plugin to a game. Mods are downloaded the next time the game is started
+func AddGameMod(gameMod *gamev1Resource.GameMod, userId uuid.UUID, name string, body gamev1Resource.AddModBody) error {
+	return db.DB().RunInTransaction(context.Background(), func(tx *pg.Tx) error {
+		var foundGame gamev1Model.Game
+		if err := getByNameAndUserId(tx, &foundGame, name, userId); err != nil {
+			if err == pg.ErrNoRows {
+				return ErrGameServerNotFound
+			}
+			return err
+		}
+
+		artifact, err := mods.Resolve(body.Source, body.ID, foundGame.Type, foundGame.Version)
+		if err != nil {
+			return &ErrResolvingMod{err}
+		}
+
+		modModel := gamev1Model.GameMod{
+			GameID:    foundGame.ID,
+			Source:    body.Source,
+			ProjectID: body.ID,
+			Name:      artifact.Name,
+			Version:   artifact.Version,
+			Kind:      artifact.Kind,
+			URL:       artifact.URL,
+		}
+
+		exists, err := tx.Model((*gamev1Model.GameMod)(nil)).
+			Where("game_id = ?", modModel.GameID).
+			Where("source = ?", modModel.Source).
+			Where("project_id = ?", modModel.ProjectID).
+			Exists()
+		if err != nil {
+			return err
+		} else if exists {
+			return ErrModAlreadyAdded
+		}
+
+		if _, err := tx.Model(&modModel).Returning("*").Insert(); err != nil {
+			return err
+		}
+
+		gameMod.MergeGameMod(&modModel)
+
+		return nil
+	})
+}
+
+func RemoveGameMod(userId uuid.UUID, name string, modId uuid.UUID) error {
+	return db.DB().RunInTransaction(context.Background(), func(tx *pg.Tx) error {
+		var foundGame gamev1Model.Game
+		if err := getByNameAndUserId(tx, &foundGame, name, userId); err != nil {
+			if err == pg.ErrNoRows {
+				return ErrGameServerNotFound
+			}
+			return err
+		}
+
+		res, err := tx.Model((*gamev1Model.GameMod)(nil)).
+			Where("id = ?", modId).
+			Where("game_id = ?", foundGame.ID).
+			Delete()
+		if err != nil {
+			return err
+		}
+
+		if res.RowsAffected() == 0 {
+			return ErrGameModNotFound
+		}
+
+		return nil
+	})
+}
+
+func getGameMods(tx *pg.Tx, gameId uuid.UUID) ([]*gamev1Model.GameMod, error) {
+	foundMods := []*gamev1Model.GameMod{}
+	if err := tx.Model(&foundMods).Where("game_id = ?", gameId).Order("created_at ASC").Select(); err != nil && err != pg.ErrNoRows {
+		return nil, err
+	}
+	return foundMods, nil
+}
@@ -0,0 +1,155 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"testing"
+
+	"github.com/go-pg/migrations/v8"
+	"github.com/spf13/viper"
+
+	"agones-minecraft/config"
+	"agones-minecraft/db"
+	gamev1Model "agones-minecraft/models/v1/game"
+	userv1Model "agones-minecraft/models/v1/user"
+	gamev1Resource "agones-minecraft/resources/api/v1/game"
+	"agones-minecraft/services/mods"
+)
+
+// Database tests run against the database configured by the DB_* environment variables and are skipped without DB_HOST
+func TestMain(m *testing.M) {
+	viper.AutomaticEnv()
+	viper.SetDefault(config.ENV, config.Development)
+
+	if viper.GetString(config.DB_HOST) == "" {
+		os.Exit(m.Run())
+	}
+
+	conn := db.New()
+	if err := conn.Ping(context.Background()); err != nil {
+		fmt.Printf("error pinging database: %v\n", err)
+		os.Exit(1)
+	}
+
+	collection := migrations.NewCollection().DisableSQLAutodiscover(true)
+	if err := collection.DiscoverSQLMigrations("../../../../cmd/migrations"); err != nil {
+		fmt.Printf("error discovering migrations: %v\n", err)
+		os.Exit(1)
+	}
+	for _, cmd := range []string{"init", "up"} {
+		if _, _, err := collection.Run(conn, cmd); err != nil {
+			fmt.Printf("error running migrations: %v\n", err)
+			os.Exit(1)
+		}
+	}
+	conn.Close()
+
+	db.Init()
+
+	os.Exit(m.Run())
+}
+
+// Creates a user with a game of the server type and version. Both are removed when the test ends
+func createTestGame(t *testing.T, serverType gamev1Model.ServerType, version string) *gamev1Model.Game {
+	if db.DB() == nil {
+		t.Skip("DB_HOST not set")
+	}
+
+	user := userv1Model.User{}
+	if _, err := db.DB().Model(&user).Returning("*").Insert(); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		// games and their mods are removed with the user
+		if _, err := db.DB().Model(&user).WherePK().ForceDelete(); err != nil {
+			t.Error(err)
+		}
+	})
+
+	game := gamev1Model.Game{
+		UserID:   user.ID,
+		Name:     "mods-test",
+		Address:  "mods-test.example.com",
+		Edition:  gamev1Model.JavaEdition,
+		State:    gamev1Model.Off,
+		Type:     serverType,
+		Settings: gamev1Model.DefaultSettings(),
+	}
+	game.Version = version
+	if _, err := db.DB().Model(&game).Returning("*").Insert(); err != nil {
+		t.Fatal(err)
+	}
+
+	return &game
+}
+
+// Sets the Modrinth fetcher to a fake. Remote fetchers are never registered in tests
+func setFakeModrinth() {
+	fake := mods.NewFakeFetcher(map[string]mods.Artifact{
+		"P7dR8mSH": {ProjectID: "P7dR8mSH", Name: "Fabric API", Version: "0.40.1", Kind: gamev1Model.ModContent, URL: "https://cdn.modrinth.com/fabric-api.jar"},
+	})
+	fake.Versions = map[string][]string{
+		"P7dR8mSH": {"1.17.1"},
+	}
+
+	mods.SetFetcher(gamev1Model.ModrinthSource, fake)
+}
+
+func TestAddGameMod(t *testing.T) {
+	setFakeModrinth()
+	game := createTestGame(t, gamev1Model.Fabric, "1.17.1")
+	body := gamev1Resource.AddModBody{Source: gamev1Model.ModrinthSource, ID: "P7dR8mSH"}
+
+	var gameMod gamev1Resource.GameMod
+	if err := AddGameMod(&gameMod, game.UserID, game.Name, body); err != nil {
+		t.Fatalf("AddGameMod() error = %v", err)
+	}
+
+	var added []*gamev1Resource.GameMod
+	if err := ListGameMods(&added, game.UserID, game.Name); err != nil {
+		t.Fatalf("ListGameMods() error = %v", err)
+	}
+	if len(added) != 1 {
+		t.Fatalf("ListGameMods() returned %d mods, want 1", len(added))
+	}
+
+	if err := AddGameMod(&gamev1Resource.GameMod{}, game.UserID, game.Name, body); err != ErrModAlreadyAdded {
+		t.Errorf("AddGameMod() duplicate error = %v, want %v", err, ErrModAlreadyAdded)
+	}
+}
+
+func TestAddGameModErrors(t *testing.T) {
+	setFakeModrinth()
+	game := createTestGame(t, gamev1Model.Fabric, "1.17.1")
+	unsupported := createTestGame(t, gamev1Model.Fabric, "1.16.5")
+
+	tests := []struct {
+		name     string
+		game     *gamev1Model.Game
+		gameName string
+		id       string
+		wantErr  error
+	}{
+		{"mod not found", game, game.Name, "unknown", mods.ErrModNotFound},
+		{"unsupported version", unsupported, unsupported.Name, "P7dR8mSH", mods.ErrNoCompatibleFile},
+		{"game not found", game, "unknown", "P7dR8mSH", ErrGameServerNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body := gamev1Resource.AddModBody{Source: gamev1Model.ModrinthSource, ID: tt.id}
+
+			err := AddGameMod(&gamev1Resource.GameMod{}, tt.game.UserID, tt.gameName, body)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("AddGameMod() error = %v, want %v", err, tt.wantErr)
+			}
+
+			var resolving *ErrResolvingMod
+			if tt.wantErr != ErrGameServerNotFound && !errors.As(err, &resolving) {
+				t.Errorf("AddGameMod() error = %T, want *ErrResolvingMod", err)
+			}
+		})
+	}
+}
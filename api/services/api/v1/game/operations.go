@@ -0,0 +1,289 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	agonesv1 "agones.dev/agones/pkg/apis/agones/v1"
+	"github.com/go-pg/pg/v10"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+
+	"agones-minecraft/db"
+	gamev1Model "agones-minecraft/models/v1/game"
+	gamev1Resource "agones-minecraft/resources/api/v1/game"
+	"agones-minecraft/services/k8s/agones"
+)
+
+// Pods are not watched so starting pods are checked on an interval until they are running
+const podStartingPollInterval time.Duration = time.Second * 5
+
+var ErrOperationNotFound error = errors.New("operation not found")
+
+// Gets an operation on one of a user's games
+func GetOperation(operation *gamev1Resource.Operation, userId uuid.UUID, id uuid.UUID) error {
+	var foundOperation gamev1Model.Operation
+	if err := db.DB().Model(&foundOperation).
+		Where("id = ?", id).
+		Where("user_id = ?", userId).
+		First(); err != nil {
+		if err == pg.ErrNoRows {
+			return ErrOperationNotFound
+		}
+		return err
+	}
+
+	operation.MergeOperation(&foundOperation)
+
+	return nil
+}
+
+// Records a new operation for a game. Unfinished operations for the game are failed as superseded
+func newOperation(tx *pg.Tx, game *gamev1Model.Game, operationType gamev1Model.OperationType) (*gamev1Model.Operation, error) {
+	now := time.Now()
+
+	if _, err := tx.Model((*gamev1Model.Operation)(nil)).
+		Set("status = ?", gamev1Model.OperationFailed).
+		Set("reason = ?", fmt.Sprintf("superseded by %s", operationType)).
+		Set("updated_at = ?", now).
+		Set("finished_at = ?", now).
+		Where("game_id = ?", game.ID).
+		Where("finished_at IS NULL").
+		Update(); err != nil {
+		return nil, err
+	}
+
+	operation := gamev1Model.Operation{
+		GameID: game.ID,
+		UserID: game.UserID,
+		Type:   operationType,
+		Status: gamev1Model.OperationQueued,
+	}
+
+	if _, err := tx.Model(&operation).Returning("*").Insert(); err != nil {
+		return nil, err
+	}
+
+	return &operation, nil
+}
+
+// Updates the progress of unfinished operations from their game's GameServer. Games are tracked by ID
+// whenever their GameServer changes and when all unfinished operations are enqueued
+type OperationTracker struct {
+	queue workqueue.RateLimitingInterface
+}
+
+// Creates a tracker enqueuing games on GameServer changes. Requires the Agones client to be initialized
+func NewOperationTracker() *OperationTracker {
+	t := &OperationTracker{
+		queue: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "operations"),
+	}
+
+	agones.Client().AddEventHandler(t.handlers())
+
+	return t
+}
+
+// Tracks enqueued games until the queue is shut down. Failed games are retried with backoff
+func (t *OperationTracker) Run() {
+	for t.processNext() {
+	}
+}
+
+// Enqueues every game with an unfinished operation
+func (t *OperationTracker) EnqueueUnfinished() error {
+	operations := []*gamev1Model.Operation{}
+	if err := db.DB().Model(&operations).
+		Column("game_id").
+		Where("finished_at IS NULL").
+		Select(); err != nil && err != pg.ErrNoRows {
+		return err
+	}
+
+	for _, operation := range operations {
+		t.queue.Add(operation.GameID)
+	}
+
+	return nil
+}
+
+func (t *OperationTracker) processNext() bool {
+	item, shutdown := t.queue.Get()
+	if shutdown {
+		return false
+	}
+	defer t.queue.Done(item)
+
+	id := item.(uuid.UUID)
+
+	if err := t.track(id); err != nil {
+		zap.L().Warn("error tracking game operations", zap.String("game", id.String()), zap.Error(err))
+		t.queue.AddRateLimited(item)
+		return true
+	}
+
+	t.queue.Forget(item)
+	return true
+}
+
+func (t *OperationTracker) track(id uuid.UUID) error {
+	gs, err := agones.Client().GetByUUID(id)
+	if err != nil {
+		if !k8sErrors.IsNotFound(err) {
+			return err
+		}
+		gs = nil
+	}
+
+	return db.DB().RunInTransaction(context.Background(), func(tx *pg.Tx) error {
+		operations := []*gamev1Model.Operation{}
+		if err := tx.Model(&operations).
+			Where("game_id = ?", id).
+			Where("finished_at IS NULL").
+			For("UPDATE SKIP LOCKED").
+			Select(); err != nil && err != pg.ErrNoRows {
+			return err
+		}
+
+		if len(operations) == 0 {
+			return nil
+		}
+
+		// GameServers are not created or deleted until the game's outbox messages are applied
+		pending, err := pendingOutboxGames(tx, id)
+		if err != nil {
+			return err
+		}
+
+		for _, operation := range operations {
+			status, reason, err := t.operationStatus(operation, gs, pending[id])
+			if err != nil {
+				return err
+			}
+
+			if status == gamev1Model.OperationPodStarting {
+				t.queue.AddAfter(id, podStartingPollInterval)
+			}
+
+			if err := updateOperation(tx, operation, status, reason); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+}
+
+// Returns the progress of an operation from its game's GameServer
+func (t *OperationTracker) operationStatus(operation *gamev1Model.Operation, gs *agonesv1.GameServer, pending bool) (gamev1Model.OperationStatus, string, error) {
+	if pending {
+		return gamev1Model.OperationQueued, "", nil
+	}
+
+	switch operation.Type {
+	case gamev1Model.StopOperation, gamev1Model.DeleteOperation:
+		if gs == nil {
+			return gamev1Model.OperationDone, "", nil
+		}
+		return gamev1Model.OperationQueued, "", nil
+	}
+
+	if gs == nil || gs.DeletionTimestamp != nil {
+		// the informer may not have seen a new GameServer yet
+		if since := time.Since(operation.CreatedAt); gs == nil && since < reconcileGracePeriod {
+			t.queue.AddAfter(operation.GameID, reconcileGracePeriod-since)
+			return operation.Status, "", nil
+		}
+		return gamev1Model.OperationFailed, "game server was deleted before it was ready", nil
+	}
+
+	switch gs.Status.State {
+	case agonesv1.GameServerStateReady, agonesv1.GameServerStateAllocated, agonesv1.GameServerStateReserved:
+		return gamev1Model.OperationReady, "", nil
+	case agonesv1.GameServerStateRequestReady:
+		return gamev1Model.OperationWorldLoading, "", nil
+	case agonesv1.GameServerStateScheduled:
+		pod, err := agones.Client().GetPod(gs)
+		if err != nil && !k8sErrors.IsNotFound(err) {
+			return "", "", err
+		}
+		if pod != nil && pod.Status.Phase == corev1.PodRunning {
+			return gamev1Model.OperationWorldLoading, "", nil
+		}
+		return gamev1Model.OperationPodStarting, "", nil
+	case agonesv1.GameServerStateError, agonesv1.GameServerStateUnhealthy, agonesv1.GameServerStateShutdown:
+		reason, err := agones.Client().GetLatestWarning(gs)
+		if err != nil {
+			zap.L().Warn("error getting game server failure reason", zap.String("gameserver", gs.Name), zap.Error(err))
+		}
+		if reason == "" {
+			reason = fmt.Sprintf("game server is %s", gs.Status.State)
+		}
+		return gamev1Model.OperationFailed, reason, nil
+	default:
+		return gamev1Model.OperationScheduled, "", nil
+	}
+}
+
+// Sets an operation's progress. Finished operations are never updated again
+func updateOperation(tx *pg.Tx, operation *gamev1Model.Operation, status gamev1Model.OperationStatus, reason string) error {
+	if operation.Status == status && operation.Reason == reason {
+		return nil
+	}
+
+	now := time.Now()
+
+	operation.Status = status
+	operation.Reason = reason
+	operation.UpdatedAt = now
+
+	if operation.IsFinished() {
+		operation.FinishedAt = now
+	}
+
+	_, err := tx.Model(operation).Column("status", "reason", "updated_at", "finished_at").WherePK().Update()
+	return err
+}
+
+func (t *OperationTracker) enqueueGameServer(gs *agonesv1.GameServer) {
+	if id, ok := getGameId(gs); ok {
+		t.queue.Add(id)
+	}
+}
+
+func (t *OperationTracker) handlers() cache.ResourceEventHandlerFuncs {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if gs, ok := obj.(*agonesv1.GameServer); ok {
+				t.enqueueGameServer(gs)
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldGs, ok := oldObj.(*agonesv1.GameServer)
+			if !ok {
+				return
+			}
+			newGs, ok := newObj.(*agonesv1.GameServer)
+			if !ok {
+				return
+			}
+			if oldGs.Status.State != newGs.Status.State || (oldGs.DeletionTimestamp == nil) != (newGs.DeletionTimestamp == nil) {
+				t.enqueueGameServer(newGs)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if gs, ok := obj.(*agonesv1.GameServer); ok {
+				t.enqueueGameServer(gs)
+			}
+		},
+	}
+}
@@ -0,0 +1,382 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"agones-minecraft/db"
+	gamev1Model "agones-minecraft/models/v1/game"
+	"agones-minecraft/models/v1/model"
+	"agones-minecraft/services/k8s/agones"
+)
+
+const (
+	// Messages applied per run. Remaining messages are applied on the next run
+	outboxBatchSize int = 20
+
+	// Claimed messages are applied again by any worker once their claim expires. Longer than a message
+	// takes to apply, including its world backup, so that messages are not applied by two workers at once
+	outboxClaimTimeout time.Duration = time.Minute * 10
+
+	// Backoff between failed attempts of a message doubles up to the max
+	outboxMinBackoff time.Duration = time.Second * 5
+	outboxMaxBackoff time.Duration = time.Minute * 10
+
+	// Applied and failed messages are kept this long for debugging before they are pruned
+	outboxRetention time.Duration = time.Hour * 24 * 7
+
+	// Attempts at backing up a stopping game's world before the stop is given up
+	stopBackupAttempts int = 5
+)
+
+var (
+	ErrGameServerNameConflict error = errors.New("game server name is used by another game")
+
+	// Wakes the outbox worker after messages are committed
+	outboxNotify chan struct{} = make(chan struct{}, 1)
+
+	// Operations failed with the messages that can fail permanently
+	outboxOperationTypes map[gamev1Model.OutboxType]gamev1Model.OperationType = map[gamev1Model.OutboxType]gamev1Model.OperationType{
+		gamev1Model.CreateGameServer: gamev1Model.CreateOperation,
+		gamev1Model.StartGameServer:  gamev1Model.StartOperation,
+	}
+)
+
+// Notifies the outbox worker of committed messages
+func OutboxNotify() <-chan struct{} {
+	return outboxNotify
+}
+
+// Applies pending outbox messages. Messages for a GameServer are applied in the order they were written
+// and messages claimed by other API replicas are skipped. Each message is claimed and has its result recorded
+// in short transactions so that no rows are locked while its cluster change is applied.
+// Failed messages are retried with backoff and messages that can never succeed are failed
+func ProcessOutbox() error {
+	for i := 0; i < outboxBatchSize; i++ {
+		message, err := claimOutboxMessage()
+		if err != nil {
+			return err
+		}
+		if message == nil {
+			return nil
+		}
+
+		applyErr := applyOutboxMessage(message)
+		if applyErr != nil {
+			zap.L().Warn("error applying outbox message",
+				zap.String("type", string(message.Type)),
+				zap.String("gameserver", message.ResourceName),
+				zap.Int("attempts", message.Attempts+1),
+				zap.Error(applyErr),
+			)
+		}
+
+		if err := recordOutboxResult(message, applyErr); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Claims the next message ready to be applied. Nil when there are none
+func claimOutboxMessage() (*gamev1Model.OutboxMessage, error) {
+	var message gamev1Model.OutboxMessage
+
+	if err := db.DB().RunInTransaction(context.Background(), func(tx *pg.Tx) error {
+		if err := tx.Model(&message).
+			Where("processed_at IS NULL").
+			Where("failed_at IS NULL").
+			Where("available_at <= now()").
+			Where("(claimed_at IS NULL OR claimed_at < ?)", time.Now().Add(-outboxClaimTimeout)).
+			Where(`NOT EXISTS (
+				SELECT 1 FROM outbox AS prev
+				WHERE prev.resource_name = outbox.resource_name
+				AND prev.processed_at IS NULL
+				AND prev.failed_at IS NULL
+				AND prev.created_at < outbox.created_at
+			)`).
+			Order("created_at ASC").
+			Limit(1).
+			For("UPDATE SKIP LOCKED").
+			Select(); err != nil {
+			return err
+		}
+
+		message.ClaimedAt = time.Now()
+		_, err := tx.Model(&message).Column("claimed_at").WherePK().Update()
+		return err
+	}); err != nil {
+		if err == pg.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &message, nil
+}
+
+// Marks a claimed message applied, failed permanently or to be retried
+func recordOutboxResult(message *gamev1Model.OutboxMessage, applyErr error) error {
+	return db.DB().RunInTransaction(context.Background(), func(tx *pg.Tx) error {
+		switch {
+		case applyErr == nil:
+			message.ProcessedAt = time.Now()
+			_, err := tx.Model(message).Column("processed_at").WherePK().Update()
+			return err
+		case errors.Is(applyErr, ErrGameServerNameConflict):
+			return deadLetterOutboxMessage(tx, message, applyErr)
+		default:
+			return failOutboxMessage(tx, message, applyErr)
+		}
+	})
+}
+
+// Deletes messages applied or failed before the retention period
+func PruneOutbox() error {
+	before := time.Now().Add(-outboxRetention)
+
+	res, err := db.DB().Model((*gamev1Model.OutboxMessage)(nil)).
+		Where("processed_at < ? OR failed_at < ?", before, before).
+		Delete()
+	if err != nil {
+		return err
+	}
+
+	if res.RowsAffected() > 0 {
+		zap.L().Info("pruned outbox messages", zap.Int("messages", res.RowsAffected()))
+	}
+
+	return nil
+}
+
+// Writes a message to be applied once the transaction commits
+func enqueueOutboxMessage(tx *pg.Tx, message *gamev1Model.OutboxMessage) error {
+	_, err := tx.Model(message).Insert()
+	return err
+}
+
+// Returns which of the games have messages not yet applied. Their GameServers do not reflect the game yet
+func pendingOutboxGames(tx *pg.Tx, gameIds ...uuid.UUID) (map[uuid.UUID]bool, error) {
+	pending := make(map[uuid.UUID]bool)
+	if len(gameIds) == 0 {
+		return pending, nil
+	}
+
+	messages := []*gamev1Model.OutboxMessage{}
+	if err := tx.Model(&messages).
+		Column("game_id").
+		Where("processed_at IS NULL").
+		Where("failed_at IS NULL").
+		Where("game_id IN (?)", pg.In(gameIds)).
+		Select(); err != nil && err != pg.ErrNoRows {
+		return nil, err
+	}
+
+	for _, message := range messages {
+		pending[message.GameID] = true
+	}
+
+	return pending, nil
+}
+
+// Wakes the outbox worker without blocking. Called after transactions writing messages commit
+func notifyOutbox() {
+	select {
+	case outboxNotify <- struct{}{}:
+	default:
+	}
+}
+
+// Applies a message's cluster change. Changes are idempotent so messages applied
+// before their result was recorded are applied again safely
+func applyOutboxMessage(message *gamev1Model.OutboxMessage) error {
+	switch message.Type {
+	case gamev1Model.CreateGameServer, gamev1Model.StartGameServer:
+		return applyCreateGameServer(message)
+	case gamev1Model.StopGameServer:
+		return applyStopGameServer(message)
+	case gamev1Model.DeleteGameServer:
+		return applyDeleteGameServer(message)
+	default:
+		zap.L().Error("unknown outbox message type", zap.String("type", string(message.Type)))
+		return nil
+	}
+}
+
+func applyCreateGameServer(message *gamev1Model.OutboxMessage) error {
+	// games deleted before their GameServer was created have nothing to create
+	var game gamev1Model.Game
+	if err := db.DB().Model(&game).Where("id = ?", message.GameID).First(); err != nil {
+		if err == pg.ErrNoRows {
+			return nil
+		}
+		return err
+	}
+
+	if message.Persistent {
+		if err := createWorldVolumeClaim(&game); err != nil {
+			return err
+		}
+	}
+
+	if _, err := createServer(message.GameServer); err != nil {
+		if !k8sErrors.IsAlreadyExists(err) {
+			return err
+		}
+
+		existing, err := agones.Client().Get(message.ResourceName)
+		if err != nil {
+			return err
+		}
+
+		// names are reused by games created after a game with the same name is deleted
+		if agones.GetUUID(existing) != message.GameID {
+			return ErrGameServerNameConflict
+		}
+
+		// created by an earlier attempt that failed before it was marked processed
+		return agones.Client().CreateRCONSecret(existing)
+	}
+
+	return nil
+}
+
+// Backs up the world of an online GameServer before deleting it
+func applyStopGameServer(message *gamev1Model.OutboxMessage) error {
+	gs, err := agones.Client().Get(message.ResourceName)
+	if err != nil {
+		if k8sErrors.IsNotFound(err) {
+			return nil
+		}
+		return err
+	}
+
+	// names are reused by games created after a game with the same name is deleted
+	if agones.GetUUID(gs) != message.GameID {
+		return nil
+	}
+
+	// servers that are not online have nothing newer than their last backup
+	if agones.IsOnline(gs) {
+		if err := agones.Client().BackupWorld(gs); err != nil {
+			if message.Attempts+1 < stopBackupAttempts {
+				return &ErrBackingUpGame{err}
+			}
+			return abandonStop(message, err)
+		}
+	}
+
+	if err := agones.Client().Delete(gs.Name); err != nil && !k8sErrors.IsNotFound(err) {
+		return &ErrDeletingGameFromK8S{err}
+	}
+
+	return nil
+}
+
+// Gives up on a stop whose world could not be backed up. The GameServer is left online rather than losing
+// the world's progress, so the game is marked On again and its stop operation is failed
+func abandonStop(message *gamev1Model.OutboxMessage, backupErr error) error {
+	zap.L().Warn("giving up stopping game after failed world backups",
+		zap.String("gameserver", message.ResourceName),
+		zap.Int("attempts", message.Attempts+1),
+		zap.Error(backupErr),
+	)
+
+	return db.DB().RunInTransaction(context.Background(), func(tx *pg.Tx) error {
+		// deleted games have their GameServer deleted by the delete message after this one
+		var game gamev1Model.Game
+		if err := tx.Model(&game).Where("id = ?", message.GameID).First(); err != nil {
+			if err == pg.ErrNoRows {
+				return nil
+			}
+			return err
+		}
+
+		if err := setGameState(tx, &game, gamev1Model.On); err != nil {
+			return err
+		}
+
+		return failOperations(tx, game.ID, gamev1Model.StopOperation, fmt.Sprintf("world backup failed: %s", backupErr))
+	})
+}
+
+// Fails a game's unfinished operations of a type. Operations superseded by a later one are already finished
+func failOperations(tx *pg.Tx, gameId uuid.UUID, operationType gamev1Model.OperationType, reason string) error {
+	operations := []*gamev1Model.Operation{}
+	if err := tx.Model(&operations).
+		Where("game_id = ?", gameId).
+		Where("type = ?", operationType).
+		Where("finished_at IS NULL").
+		Select(); err != nil && err != pg.ErrNoRows {
+		return err
+	}
+
+	for _, operation := range operations {
+		if err := updateOperation(tx, operation, gamev1Model.OperationFailed, reason); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func applyDeleteGameServer(message *gamev1Model.OutboxMessage) error {
+	if err := agones.Client().Delete(message.ResourceName); err != nil && !k8sErrors.IsNotFound(err) {
+		return err
+	}
+
+	// persistent worlds are only removed with their game
+	if message.Persistent {
+		game := gamev1Model.Game{Model: model.Model{ID: message.GameID}}
+		if err := deleteWorldVolumeClaim(&game); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func failOutboxMessage(tx *pg.Tx, message *gamev1Model.OutboxMessage, applyErr error) error {
+	message.Attempts++
+	message.LastError = applyErr.Error()
+
+	backoff := outboxMinBackoff
+	for i := 1; i < message.Attempts && backoff < outboxMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > outboxMaxBackoff {
+		backoff = outboxMaxBackoff
+	}
+
+	message.AvailableAt = time.Now().Add(backoff)
+	message.ClaimedAt = time.Time{}
+
+	_, err := tx.Model(message).Column("attempts", "last_error", "available_at", "claimed_at").WherePK().Update()
+	return err
+}
+
+// Fails a message that can never be applied so that later messages for its GameServer are not blocked by it
+func deadLetterOutboxMessage(tx *pg.Tx, message *gamev1Model.OutboxMessage, applyErr error) error {
+	message.Attempts++
+	message.LastError = applyErr.Error()
+	message.FailedAt = time.Now()
+
+	if _, err := tx.Model(message).Column("attempts", "last_error", "failed_at").WherePK().Update(); err != nil {
+		return err
+	}
+
+	operationType, ok := outboxOperationTypes[message.Type]
+	if !ok {
+		return nil
+	}
+
+	return failOperations(tx, message.GameID, operationType, applyErr.Error())
+}
@@ -0,0 +1,252 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	agonesv1 "agones.dev/agones/pkg/apis/agones/v1"
+	"github.com/go-pg/pg/v10"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"agones-minecraft/db"
+	gamev1Model "agones-minecraft/models/v1/game"
+	mcv1Model "agones-minecraft/models/v1/mc"
+	gamev1Resource "agones-minecraft/resources/api/v1/game"
+	"agones-minecraft/services/k8s/agones"
+	"agones-minecraft/services/mc"
+)
+
+var (
+	ErrPlayerListsUnsupported error = errors.New("whitelists and operators are only supported for java edition games")
+)
+
+func GetPlayers(players *[]*gamev1Resource.Player, list gamev1Model.PlayerList, userId uuid.UUID, name string) error {
+	return db.DB().RunInTransaction(context.Background(), func(tx *pg.Tx) error {
+		var foundGame gamev1Model.Game
+		if err := getByNameAndUserId(tx, &foundGame, name, userId); err != nil {
+			if err == pg.ErrNoRows {
+				return ErrGameServerNotFound
+			}
+			return err
+		}
+
+		foundPlayers, err := getGamePlayers(tx, list, foundGame.ID)
+		if err != nil {
+			return err
+		}
+
+		for _, foundPlayer := range foundPlayers {
+			player := gamev1Resource.Player{}
+			player.MergeGamePlayer(foundPlayer)
+			*players = append(*players, &player)
+		}
+
+		return nil
+	})
+}
+
+// Replaces a game's whitelist or operators with the given Minecraft usernames.
+// Changes are applied over RCON when the game is online and through the server's env on its next start
+func SetPlayers(players *[]*gamev1Resource.Player, list gamev1Model.PlayerList, userId uuid.UUID, name string, body gamev1Resource.PlayerListBody) error {
+	// resolve usernames to Minecraft accounts before the transaction, dropping duplicates
+	wanted := map[uuid.UUID]string{}
+	for _, username := range body.Players {
+		mcUser, err := mc.GetUser(username)
+		if err != nil {
+			return err
+		}
+		wanted[mcUser.UUID] = mcUser.Username
+	}
+
+	var foundGame gamev1Model.Game
+	var added, removed []string
+
+	if err := db.DB().RunInTransaction(context.Background(), func(tx *pg.Tx) error {
+		if err := getByNameAndUserId(tx, &foundGame, name, userId); err != nil {
+			if err == pg.ErrNoRows {
+				return ErrGameServerNotFound
+			}
+			return err
+		}
+
+		if foundGame.Edition != gamev1Model.JavaEdition {
+			return ErrPlayerListsUnsupported
+		}
+
+		// owners are always operators on their own servers
+		if list == gamev1Model.Ops {
+			owner, err := getOwnerMCAccount(tx, foundGame.UserID)
+			if err != nil {
+				return err
+			} else if owner != nil {
+				wanted[owner.MCID] = owner.Username
+			}
+		}
+
+		foundPlayers, err := getGamePlayers(tx, list, foundGame.ID)
+		if err != nil {
+			return err
+		}
+
+		for _, foundPlayer := range foundPlayers {
+			if _, ok := wanted[foundPlayer.MCID]; ok {
+				delete(wanted, foundPlayer.MCID)
+				continue
+			}
+			if _, err := tx.Model(foundPlayer).ModelTableExpr(playerTable(list)).WherePK().Delete(); err != nil {
+				return err
+			}
+			removed = append(removed, foundPlayer.Username)
+		}
+
+		for mcId, username := range wanted {
+			playerModel := gamev1Model.GamePlayer{
+				GameID:   foundGame.ID,
+				MCID:     mcId,
+				Username: username,
+			}
+			if _, err := tx.Model(&playerModel).ModelTableExpr(playerTable(list)).Insert(); err != nil {
+				return err
+			}
+			added = append(added, username)
+		}
+
+		if foundPlayers, err = getGamePlayers(tx, list, foundGame.ID); err != nil {
+			return err
+		}
+
+		for _, foundPlayer := range foundPlayers {
+			player := gamev1Resource.Player{}
+			player.MergeGamePlayer(foundPlayer)
+			*players = append(*players, &player)
+		}
+
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	// applied once the lists are committed so that live servers never have changes the game does not
+	applyPlayers(&foundGame, list, added, removed)
+
+	return nil
+}
+
+// Adds the game owner's linked Minecraft account to the game's operators and returns the game's operators.
+// Owners without a linked Minecraft account are skipped
+func ensureOwnerOp(tx *pg.Tx, game *gamev1Model.Game) ([]*gamev1Model.GamePlayer, error) {
+	owner, err := getOwnerMCAccount(tx, game.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	if owner != nil {
+		exists, err := tx.Model((*gamev1Model.GamePlayer)(nil)).
+			ModelTableExpr(playerTable(gamev1Model.Ops)).
+			Where("game_id = ?", game.ID).
+			Where("mc_id = ?", owner.MCID).
+			Exists()
+		if err != nil {
+			return nil, err
+		}
+
+		if !exists {
+			ownerOp := gamev1Model.GamePlayer{
+				GameID:   game.ID,
+				MCID:     owner.MCID,
+				Username: owner.Username,
+			}
+			if _, err := tx.Model(&ownerOp).ModelTableExpr(playerTable(gamev1Model.Ops)).Insert(); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return getGamePlayers(tx, gamev1Model.Ops, game.ID)
+}
+
+// Returns the user's linked Minecraft account or nil if they have not linked one
+func getOwnerMCAccount(tx *pg.Tx, userId uuid.UUID) (*mcv1Model.MCAccount, error) {
+	var mcAccount mcv1Model.MCAccount
+	if err := tx.Model(&mcAccount).Where("user_id = ?", userId).Select(); err != nil {
+		if err == pg.ErrNoRows {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &mcAccount, nil
+}
+
+// Applies player list changes to an online game over RCON.
+// Failures are logged since the lists are reapplied through the server's env on its next start
+func applyPlayers(game *gamev1Model.Game, list gamev1Model.PlayerList, added, removed []string) {
+	if len(added) == 0 && len(removed) == 0 {
+		return
+	}
+
+	gs, err := agones.Client().GetForUser(game.GetResourceName(), game.UserID)
+	if err != nil {
+		if !k8sErrors.IsNotFound(err) {
+			zap.L().Warn("error getting game server to apply player list", zap.String("game", game.Name), zap.Error(err))
+		}
+		return
+	}
+
+	if !agones.IsOnline(gs) {
+		return
+	}
+
+	addCmd, removeCmd := "whitelist add", "whitelist remove"
+	if list == gamev1Model.Ops {
+		addCmd, removeCmd = "op", "deop"
+	}
+
+	var cmds []string
+	for _, username := range added {
+		cmds = append(cmds, fmt.Sprintf("%s %s", addCmd, username))
+	}
+	for _, username := range removed {
+		cmds = append(cmds, fmt.Sprintf("%s %s", removeCmd, username))
+	}
+
+	if err := runCommands(gs, cmds); err != nil {
+		zap.L().Warn("error applying player list over rcon", zap.String("game", game.Name), zap.String("list", string(list)), zap.Error(err))
+	}
+}
+
+// Runs commands over a single RCON connection to the GameServer
+func runCommands(gs *agonesv1.GameServer, cmds []string) error {
+	client, err := agones.Client().RCON(gs)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	for _, cmd := range cmds {
+		if _, err := client.Command(cmd); err != nil {
+			return fmt.Errorf("%s: %w", strings.Fields(cmd)[0], err)
+		}
+	}
+
+	return nil
+}
+
+func getGamePlayers(tx *pg.Tx, list gamev1Model.PlayerList, gameId uuid.UUID) ([]*gamev1Model.GamePlayer, error) {
+	foundPlayers := []*gamev1Model.GamePlayer{}
+	if err := tx.Model(&foundPlayers).
+		ModelTableExpr(playerTable(list)).
+		Where("game_id = ?", gameId).
+		Order("username ASC").
+		Select(); err != nil && err != pg.ErrNoRows {
+		return nil, err
+	}
+	return foundPlayers, nil
+}
+
+func playerTable(list gamev1Model.PlayerList) string {
+	return string(list) + " AS game_player"
+}
@@ -0,0 +1,200 @@
+package game
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/google/uuid"
+
+	"agones-minecraft/db"
+	gamev1Model "agones-minecraft/models/v1/game"
+	userv1Model "agones-minecraft/models/v1/user"
+	userv1Resource "agones-minecraft/resources/api/v1/user"
+	userv1Service "agones-minecraft/services/api/v1/user"
+	"agones-minecraft/services/k8s/agones"
+	"agones-minecraft/services/storage"
+)
+
+// Error for games that would exceed their user's plan
+type ErrQuotaExceeded struct {
+	error
+}
+
+func newQuotaError(format string, a ...interface{}) *ErrQuotaExceeded {
+	return &ErrQuotaExceeded{fmt.Errorf(format, a...)}
+}
+
+// A user's games counted against their plan
+type planUsage struct {
+	games         int
+	runningGames  int
+	memory        int
+	backupStorage int64
+}
+
+// Gets a user's plan and its current usage
+func GetPlan(plan *userv1Resource.Plan, userId uuid.UUID) error {
+	backupStorage, err := getBackupUsage(userId)
+	if err != nil {
+		return err
+	}
+
+	return db.DB().RunInTransaction(context.Background(), func(tx *pg.Tx) error {
+		var foundPlan userv1Model.Plan
+		if err := userv1Service.GetPlanForUser(tx, &foundPlan, userId); err != nil {
+			return err
+		}
+
+		usage, err := getPlanUsage(tx, userId, uuid.Nil, backupStorage)
+		if err != nil {
+			return err
+		}
+
+		plan.MergePlan(&foundPlan)
+		plan.Usage = userv1Resource.PlanUsage{
+			Games:        usage.games,
+			RunningGames: usage.runningGames,
+			Memory:       usage.memory,
+			// rounded up so that any usage shows
+			BackupStorage: int((usage.backupStorage + (1 << 20) - 1) >> 20),
+		}
+
+		return nil
+	})
+}
+
+// Checks that a new game fits in its user's plan. The user is locked until the transaction ends
+// so that concurrent requests are counted one after another
+func checkCreateQuota(tx *pg.Tx, game *gamev1Model.Game, backupStorage int64) error {
+	if err := lockUser(tx, game.UserID); err != nil {
+		return err
+	}
+
+	var plan userv1Model.Plan
+	if err := userv1Service.GetPlanForUser(tx, &plan, game.UserID); err != nil {
+		return err
+	}
+
+	usage, err := getPlanUsage(tx, game.UserID, game.ID, backupStorage)
+	if err != nil {
+		return err
+	}
+
+	if plan.MaxGames > 0 && usage.games+1 > plan.MaxGames {
+		return newQuotaError("plan %s allows up to %d games", plan.Name, plan.MaxGames)
+	}
+
+	return checkRunQuota(&plan, usage, game)
+}
+
+// Checks that a stopped game can be started within its user's plan. The user is locked until the
+// transaction ends so that concurrent requests are counted one after another
+func checkStartQuota(tx *pg.Tx, game *gamev1Model.Game, backupStorage int64) error {
+	if err := lockUser(tx, game.UserID); err != nil {
+		return err
+	}
+
+	var plan userv1Model.Plan
+	if err := userv1Service.GetPlanForUser(tx, &plan, game.UserID); err != nil {
+		return err
+	}
+
+	usage, err := getPlanUsage(tx, game.UserID, game.ID, backupStorage)
+	if err != nil {
+		return err
+	}
+
+	return checkRunQuota(&plan, usage, game)
+}
+
+// Locks a user's row until the transaction ends
+func lockUser(tx *pg.Tx, userId uuid.UUID) error {
+	var user userv1Model.User
+	return tx.Model(&user).
+		Column("u.id").
+		Where("u.id = ?", userId).
+		For("UPDATE").
+		Select()
+}
+
+func checkRunQuota(plan *userv1Model.Plan, usage *planUsage, game *gamev1Model.Game) error {
+	if plan.MaxRunningGames > 0 && usage.runningGames+1 > plan.MaxRunningGames {
+		return newQuotaError("plan %s allows up to %d games online at once", plan.Name, plan.MaxRunningGames)
+	}
+
+	if plan.MaxSlots > 0 && game.Slots > plan.MaxSlots {
+		return newQuotaError("plan %s allows up to %d slots per game", plan.Name, plan.MaxSlots)
+	}
+
+	if plan.MaxMemory > 0 && usage.memory+gameMemory(game) > plan.MaxMemory {
+		return newQuotaError("plan %s allows up to %dMiB of memory across online games", plan.Name, plan.MaxMemory)
+	}
+
+	if plan.MaxBackupStorage > 0 && usage.backupStorage > int64(plan.MaxBackupStorage)<<20 {
+		return newQuotaError("plan %s allows up to %dMiB of world backups. delete backups or lower backup retention", plan.Name, plan.MaxBackupStorage)
+	}
+
+	return nil
+}
+
+// Returns a user's usage with their backup storage, not counting the game being created or started
+func getPlanUsage(tx *pg.Tx, userId uuid.UUID, excludeGameId uuid.UUID, backupStorage int64) (*planUsage, error) {
+	foundGames := []*gamev1Model.Game{}
+	if err := tx.Model(&foundGames).
+		Where("user_id = ?", userId).
+		Select(); err != nil && err != pg.ErrNoRows {
+		return nil, err
+	}
+
+	usage := planUsage{backupStorage: backupStorage}
+
+	for _, foundGame := range foundGames {
+		if foundGame.ID == excludeGameId {
+			continue
+		}
+
+		usage.games++
+
+		if foundGame.State == gamev1Model.On {
+			usage.runningGames++
+			usage.memory += gameMemory(foundGame)
+		}
+	}
+
+	return &usage, nil
+}
+
+// Returns the size in bytes of the world backups of a user's games. Called before transactions
+// are opened so that storage is not listed while rows are locked
+func getBackupUsage(userId uuid.UUID) (int64, error) {
+	foundGames := []*gamev1Model.Game{}
+	if err := db.DB().Model(&foundGames).
+		Column("user_id", "name").
+		Where("user_id = ?", userId).
+		Select(); err != nil && err != pg.ErrNoRows {
+		return 0, err
+	}
+
+	var size int64
+
+	for _, foundGame := range foundGames {
+		objects, err := storage.Get().List(agones.BackupPrefix(foundGame.GetResourceName()))
+		if err != nil {
+			return 0, err
+		}
+
+		for _, object := range objects {
+			if _, ok := agones.ParseBackupTime(foundGame.GetResourceName(), object.Name); ok {
+				size += object.Size
+			}
+		}
+	}
+
+	return size, nil
+}
+
+// Returns the memory in MiB a game's tier reserves while online
+func gameMemory(game *gamev1Model.Game) int {
+	return agones.GetServerTier(game.Tier).Memory
+}
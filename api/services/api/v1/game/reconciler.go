@@ -0,0 +1,216 @@
+package game
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	agonesv1 "agones.dev/agones/pkg/apis/agones/v1"
+	"github.com/go-pg/pg/v10"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/util/workqueue"
+
+	"agones-minecraft/db"
+	gamev1Model "agones-minecraft/models/v1/game"
+	"agones-minecraft/services/k8s/agones"
+)
+
+// Games and GameServers changed this recently are left alone. Requests change both in one transaction
+// so either side may not be visible to the reconciler yet
+const reconcileGracePeriod time.Duration = time.Minute * 2
+
+// Keeps game states in the database in sync with their GameServers. Games are reconciled by ID
+// whenever their GameServer changes and when all games are enqueued
+type Reconciler struct {
+	queue workqueue.RateLimitingInterface
+}
+
+// Creates a reconciler enqueuing games on GameServer changes. Requires the Agones client to be initialized
+func NewReconciler() *Reconciler {
+	r := &Reconciler{
+		queue: workqueue.NewNamedRateLimitingQueue(workqueue.DefaultControllerRateLimiter(), "games"),
+	}
+
+	agones.Client().AddEventHandler(r.handlers())
+
+	return r
+}
+
+// Reconciles enqueued games until the queue is shut down. Failed games are retried with backoff
+func (r *Reconciler) Run() {
+	for r.processNext() {
+	}
+}
+
+// Enqueues every game and every GameServer labeled with a game
+func (r *Reconciler) EnqueueAll() error {
+	foundGames := []*gamev1Model.Game{}
+	if err := db.DB().Model(&foundGames).Column("id").Select(); err != nil && err != pg.ErrNoRows {
+		return err
+	}
+
+	for _, foundGame := range foundGames {
+		r.queue.Add(foundGame.ID)
+	}
+
+	gsList, err := agones.Client().List()
+	if err != nil {
+		return err
+	}
+
+	for _, gs := range gsList {
+		r.enqueueGameServer(gs)
+	}
+
+	return nil
+}
+
+func (r *Reconciler) processNext() bool {
+	item, shutdown := r.queue.Get()
+	if shutdown {
+		return false
+	}
+	defer r.queue.Done(item)
+
+	id := item.(uuid.UUID)
+
+	if err := r.reconcile(id); err != nil {
+		zap.L().Warn("error reconciling game", zap.String("game", id.String()), zap.Error(err))
+		r.queue.AddRateLimited(item)
+		return true
+	}
+
+	r.queue.Forget(item)
+	return true
+}
+
+func (r *Reconciler) reconcile(id uuid.UUID) error {
+	gs, err := agones.Client().GetByUUID(id)
+	if err != nil {
+		if !k8sErrors.IsNotFound(err) {
+			return err
+		}
+		gs = nil
+	}
+
+	var game gamev1Model.Game
+	if err := db.DB().Model(&game).Where("id = ?", id).First(); err != nil {
+		if err == pg.ErrNoRows {
+			if gs != nil {
+				return r.flagOrphan(id, gs)
+			}
+			return nil
+		}
+		return err
+	}
+
+	if since := time.Since(game.UpdatedAt); since < reconcileGracePeriod {
+		r.queue.AddAfter(id, reconcileGracePeriod-since)
+		return nil
+	}
+
+	realState := agones.GetState(gs)
+	if realState == game.State {
+		return nil
+	}
+
+	// GameServers deleted outside of the API, e.g. by node failures or kubectl, have no backup taken on
+	// stop. Their games are marked Off and restore their latest backup when started
+	if gs == nil {
+		zap.L().Warn("game server disappeared. marking game off", zap.String("game", game.GetResourceName()))
+	}
+
+	return db.DB().RunInTransaction(context.Background(), func(tx *pg.Tx) error {
+		// reconciled again once the outbox worker applies the game's messages
+		pending, err := pendingOutboxGames(tx, game.ID)
+		if err != nil || pending[game.ID] {
+			return err
+		}
+
+		return setGameState(tx, &game, realState)
+	})
+}
+
+// Labels a GameServer without a game so that it can be cleaned up
+func (r *Reconciler) flagOrphan(id uuid.UUID, gs *agonesv1.GameServer) error {
+	if gs.Labels[agones.OrphanedLabel] == "true" {
+		return nil
+	}
+
+	if since := time.Since(gs.CreationTimestamp.Time); since < reconcileGracePeriod {
+		r.queue.AddAfter(id, reconcileGracePeriod-since)
+		return nil
+	}
+
+	flagged := gs.DeepCopy()
+	flagged.Labels[agones.OrphanedLabel] = "true"
+
+	if _, err := agones.Client().Update(flagged); err != nil {
+		return err
+	}
+
+	message := fmt.Sprintf("No game found for GameServer owned by user %s", agones.GetUserId(gs))
+	if err := agones.Client().RecordEvent(gs, corev1.EventTypeWarning, agones.OrphanedReason, message); err != nil {
+		zap.L().Warn("error recording orphaned game server event", zap.String("gameserver", gs.Name), zap.Error(err))
+	}
+
+	zap.L().Warn("flagged orphaned game server", zap.String("gameserver", gs.Name))
+
+	return nil
+}
+
+// Enqueues GameServers created by the API. Only GameServers labeled with a user and game are reconciled
+func (r *Reconciler) enqueueGameServer(gs *agonesv1.GameServer) {
+	if id, ok := getGameId(gs); ok {
+		r.queue.Add(id)
+	}
+}
+
+// Returns the game of a GameServer created by the API. Not ok for GameServers without a user and game label
+func getGameId(gs *agonesv1.GameServer) (uuid.UUID, bool) {
+	if agones.GetUserId(gs) == "" {
+		return uuid.Nil, false
+	}
+
+	id, err := uuid.Parse(gs.Labels[agones.UUIDLabel])
+	if err != nil {
+		return uuid.Nil, false
+	}
+
+	return id, true
+}
+
+func (r *Reconciler) handlers() cache.ResourceEventHandlerFuncs {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if gs, ok := obj.(*agonesv1.GameServer); ok {
+				r.enqueueGameServer(gs)
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldGs, ok := oldObj.(*agonesv1.GameServer)
+			if !ok {
+				return
+			}
+			newGs, ok := newObj.(*agonesv1.GameServer)
+			if !ok {
+				return
+			}
+			if agones.GetState(oldGs) != agones.GetState(newGs) {
+				r.enqueueGameServer(newGs)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if gs, ok := obj.(*agonesv1.GameServer); ok {
+				r.enqueueGameServer(gs)
+			}
+		},
+	}
+}
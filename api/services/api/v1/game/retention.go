@@ -0,0 +1,105 @@
+package game
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-pg/pg/v10"
+	"go.uber.org/zap"
+
+	"agones-minecraft/db"
+	gamev1Model "agones-minecraft/models/v1/game"
+	gamev1Resource "agones-minecraft/resources/api/v1/game"
+	"agones-minecraft/services/storage"
+)
+
+// Deletes backups outside of each game's retention settings. Games that fail are logged and skipped
+func EnforceBackupRetention() error {
+	foundGames := []*gamev1Model.Game{}
+	if err := db.DB().Model(&foundGames).Select(); err != nil && err != pg.ErrNoRows {
+		return err
+	}
+
+	for _, foundGame := range foundGames {
+		if err := enforceGameBackupRetention(foundGame); err != nil {
+			zap.L().Warn("error enforcing backup retention", zap.String("game", foundGame.GetResourceName()), zap.Error(err))
+		}
+	}
+
+	return nil
+}
+
+func enforceGameBackupRetention(game *gamev1Model.Game) error {
+	backups, err := getBackups(game)
+	if err != nil {
+		return err
+	}
+
+	for _, backup := range expiredBackups(backups, game.BackupSettings) {
+		// backups waiting to be restored are kept until the game is started
+		if backup.Name == game.RestoreBackup {
+			continue
+		}
+
+		if err := storage.Get().Delete(backup.Name); err != nil && err != storage.ErrObjectNotFound {
+			return err
+		}
+
+		zap.L().Info("deleted expired backup", zap.String("backup", backup.Name))
+	}
+
+	return nil
+}
+
+// Returns backups outside of the retention settings. Backups must be sorted from newest to oldest
+func expiredBackups(backups []*gamev1Resource.Backup, settings gamev1Model.BackupSettings) []*gamev1Resource.Backup {
+	if settings.BackupKeepLast == 0 && settings.BackupKeepDaily == 0 && settings.BackupKeepWeekly == 0 {
+		return nil
+	}
+
+	keep := map[string]bool{}
+
+	for i, backup := range backups {
+		if i >= settings.BackupKeepLast {
+			break
+		}
+		keep[backup.Name] = true
+	}
+
+	keepNewestPerPeriod(backups, settings.BackupKeepDaily, keep, func(t time.Time) string {
+		return t.UTC().Format("2006-01-02")
+	})
+
+	keepNewestPerPeriod(backups, settings.BackupKeepWeekly, keep, func(t time.Time) string {
+		year, week := t.UTC().ISOWeek()
+		return fmt.Sprintf("%d-%d", year, week)
+	})
+
+	expired := []*gamev1Resource.Backup{}
+	for _, backup := range backups {
+		if !keep[backup.Name] {
+			expired = append(expired, backup)
+		}
+	}
+
+	return expired
+}
+
+// Keeps the newest backup of each of the last n periods that have backups
+func keepNewestPerPeriod(backups []*gamev1Resource.Backup, n int, keep map[string]bool, period func(time.Time) string) {
+	periods := map[string]bool{}
+
+	for _, backup := range backups {
+		if len(periods) >= n {
+			return
+		}
+
+		p := period(backup.CreatedAt)
+		if periods[p] {
+			continue
+		}
+
+		periods[p] = true
+		keep[backup.Name] = true
+	}
+}
@@ -0,0 +1,290 @@
+package game
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+	// schedule timezones are loaded without relying on the container's zoneinfo
+	_ "time/tzdata"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/google/uuid"
+	"github.com/robfig/cron/v3"
+	"go.uber.org/zap"
+
+	"agones-minecraft/db"
+	gamev1Model "agones-minecraft/models/v1/game"
+	gamev1Resource "agones-minecraft/resources/api/v1/game"
+)
+
+const (
+	// Maximum schedules per game
+	MaxSchedules int = 10
+
+	DefaultTimezone string = "UTC"
+
+	// Arbitrary key of the Postgres advisory lock held by the API replica running schedules
+	scheduleLockKey int64 = 4045
+
+	// Bounds the occurrences walked for schedules that have not been checked in a long time
+	maxScheduleOccurrences int = 100000
+)
+
+var (
+	ErrScheduleNotFound error = errors.New("schedule not found")
+	ErrTooManySchedules error = fmt.Errorf("games can have up to %d schedules", MaxSchedules)
+)
+
+type scheduleAction int
+
+const (
+	noAction scheduleAction = iota
+	startAction
+	stopAction
+)
+
+func ListSchedules(schedules *[]*gamev1Resource.Schedule, userId uuid.UUID, name string) error {
+	return db.DB().RunInTransaction(context.Background(), func(tx *pg.Tx) error {
+		var foundGame gamev1Model.Game
+		if err := getByNameAndUserId(tx, &foundGame, name, userId); err != nil {
+			if err == pg.ErrNoRows {
+				return ErrGameServerNotFound
+			}
+			return err
+		}
+
+		foundSchedules := []*gamev1Model.GameSchedule{}
+		if err := tx.Model(&foundSchedules).
+			Where("game_id = ?", foundGame.ID).
+			Order("created_at ASC").
+			Select(); err != nil && err != pg.ErrNoRows {
+			return err
+		}
+
+		now := time.Now()
+		for _, foundSchedule := range foundSchedules {
+			schedule := gamev1Resource.Schedule{}
+			mergeSchedule(&schedule, foundSchedule, now)
+			*schedules = append(*schedules, &schedule)
+		}
+
+		return nil
+	})
+}
+
+// Adds a start and stop schedule to a game. Times before the schedule is created are never applied
+func CreateSchedule(schedule *gamev1Resource.Schedule, userId uuid.UUID, name string, body gamev1Resource.ScheduleBody) error {
+	return db.DB().RunInTransaction(context.Background(), func(tx *pg.Tx) error {
+		var foundGame gamev1Model.Game
+		if err := getByNameAndUserId(tx, &foundGame, name, userId); err != nil {
+			if err == pg.ErrNoRows {
+				return ErrGameServerNotFound
+			}
+			return err
+		}
+
+		count, err := tx.Model((*gamev1Model.GameSchedule)(nil)).
+			Where("game_id = ?", foundGame.ID).
+			Count()
+		if err != nil {
+			return err
+		}
+
+		if count >= MaxSchedules {
+			return ErrTooManySchedules
+		}
+
+		newSchedule := gamev1Model.GameSchedule{
+			GameID:    foundGame.ID,
+			StartCron: body.Start,
+			StopCron:  body.Stop,
+			Timezone:  body.Timezone,
+			Enabled:   true,
+			CheckedAt: time.Now(),
+		}
+
+		if newSchedule.Timezone == "" {
+			newSchedule.Timezone = DefaultTimezone
+		}
+		if body.Enabled != nil {
+			newSchedule.Enabled = *body.Enabled
+		}
+
+		if _, err := tx.Model(&newSchedule).Returning("*").Insert(); err != nil {
+			return err
+		}
+
+		mergeSchedule(schedule, &newSchedule, time.Now())
+
+		return nil
+	})
+}
+
+func DeleteSchedule(userId uuid.UUID, name string, scheduleId uuid.UUID) error {
+	return db.DB().RunInTransaction(context.Background(), func(tx *pg.Tx) error {
+		var foundGame gamev1Model.Game
+		if err := getByNameAndUserId(tx, &foundGame, name, userId); err != nil {
+			if err == pg.ErrNoRows {
+				return ErrGameServerNotFound
+			}
+			return err
+		}
+
+		res, err := tx.Model((*gamev1Model.GameSchedule)(nil)).
+			Where("id = ?", scheduleId).
+			Where("game_id = ?", foundGame.ID).
+			Delete()
+		if err != nil {
+			return err
+		}
+
+		if res.RowsAffected() == 0 {
+			return ErrScheduleNotFound
+		}
+
+		return nil
+	})
+}
+
+// Starts and stops games whose schedules are due. Only one API replica runs schedules at a time.
+// Schedules that fail are logged and retried on the next run
+func RunSchedules() error {
+	return db.DB().RunInTransaction(context.Background(), func(tx *pg.Tx) error {
+		// released when the transaction ends
+		var locked bool
+		if _, err := tx.QueryOne(pg.Scan(&locked), "SELECT pg_try_advisory_xact_lock(?)", scheduleLockKey); err != nil {
+			return err
+		}
+
+		// another replica is running schedules
+		if !locked {
+			return nil
+		}
+
+		foundSchedules := []*gamev1Model.GameSchedule{}
+		if err := tx.Model(&foundSchedules).
+			Where("enabled = ?", true).
+			Where("game_id IN (SELECT id FROM games WHERE deleted_at IS NULL)").
+			Select(); err != nil && err != pg.ErrNoRows {
+			return err
+		}
+
+		now := time.Now()
+
+		for _, foundSchedule := range foundSchedules {
+			if err := runSchedule(tx, foundSchedule, now); err != nil {
+				zap.L().Warn("error running game schedule", zap.String("schedule", foundSchedule.ID.String()), zap.Error(err))
+			}
+		}
+
+		return nil
+	})
+}
+
+func runSchedule(tx *pg.Tx, schedule *gamev1Model.GameSchedule, now time.Time) error {
+	action, err := dueAction(schedule, now)
+	if err != nil {
+		return err
+	}
+
+	if action == noAction {
+		return nil
+	}
+
+	var foundGame gamev1Model.Game
+	if err := tx.Model(&foundGame).Where("id = ?", schedule.GameID).First(); err != nil {
+		if err == pg.ErrNoRows {
+			return ErrGameServerNotFound
+		}
+		return err
+	}
+
+	var game gamev1Resource.Game
+
+	switch action {
+	case startAction:
+		if err := StartGame(&game, foundGame.UserID, foundGame.Name); err != nil && err != ErrGameAlreadyStarted {
+			return err
+		}
+	case stopAction:
+		if err := StopGame(&game, foundGame.UserID, foundGame.Name); err != nil && err != ErrGameAlreadyStopped {
+			return err
+		}
+	}
+
+	schedule.CheckedAt = now
+	schedule.UpdatedAt = now
+
+	_, err = tx.Model(schedule).Column("checked_at", "updated_at").WherePK().Update()
+	return err
+}
+
+// Returns the latest of the schedule's start and stop actions due since it was last checked.
+// Stopping wins when both are due at the same time
+func dueAction(schedule *gamev1Model.GameSchedule, now time.Time) (scheduleAction, error) {
+	loc, err := time.LoadLocation(schedule.Timezone)
+	if err != nil {
+		return noAction, err
+	}
+
+	lastStart, err := lastOccurrence(schedule.StartCron, schedule.CheckedAt.In(loc), now)
+	if err != nil {
+		return noAction, err
+	}
+
+	lastStop, err := lastOccurrence(schedule.StopCron, schedule.CheckedAt.In(loc), now)
+	if err != nil {
+		return noAction, err
+	}
+
+	switch {
+	case lastStart.IsZero() && lastStop.IsZero():
+		return noAction, nil
+	case lastStart.After(lastStop):
+		return startAction, nil
+	default:
+		return stopAction, nil
+	}
+}
+
+// Returns the last time a cron schedule fires after from and at or before now. Zero when it does not fire
+func lastOccurrence(spec string, from time.Time, now time.Time) (time.Time, error) {
+	schedule, err := cron.ParseStandard(spec)
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	var last time.Time
+	for i, next := 0, schedule.Next(from); i < maxScheduleOccurrences && !next.IsZero() && !next.After(now); i++ {
+		last = next
+		next = schedule.Next(next)
+	}
+
+	return last, nil
+}
+
+// Returns the next time a cron schedule fires in a timezone. Zero for invalid schedules
+func nextOccurrence(spec string, timezone string, now time.Time) time.Time {
+	loc, err := time.LoadLocation(timezone)
+	if err != nil {
+		return time.Time{}
+	}
+
+	schedule, err := cron.ParseStandard(spec)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return schedule.Next(now.In(loc))
+}
+
+func mergeSchedule(schedule *gamev1Resource.Schedule, scheduleModel *gamev1Model.GameSchedule, now time.Time) {
+	var nextStart, nextStop time.Time
+	if scheduleModel.Enabled {
+		nextStart = nextOccurrence(scheduleModel.StartCron, scheduleModel.Timezone, now)
+		nextStop = nextOccurrence(scheduleModel.StopCron, scheduleModel.Timezone, now)
+	}
+
+	schedule.MergeGameSchedule(scheduleModel, nextStart, nextStop)
+}
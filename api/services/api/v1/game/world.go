@@ -0,0 +1,80 @@
+package game
+
+import (
+	"context"
+	"io"
+	"os"
+	"time"
+
+	"github.com/go-pg/pg/v10"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
+
+	"agones-minecraft/config"
+	"agones-minecraft/db"
+	gamev1Resource "agones-minecraft/resources/api/v1/game"
+	"agones-minecraft/services/k8s/agones"
+	"agones-minecraft/services/storage"
+	"agones-minecraft/services/world"
+)
+
+// Worlds compress well so extracted worlds may be this many times larger than the upload limit
+const worldExtractedSizeFactor int64 = 4
+
+// Validates an uploaded world archive, stores it with the game's backups and loads it the next time the game is started
+func UploadWorld(game *gamev1Resource.Game, userId uuid.UUID, name string, src io.ReaderAt, size int64, format world.Format) error {
+	foundGame, err := findGame(userId, name)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp("", "world-*.zip")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		tmp.Close()
+		if err := os.Remove(tmp.Name()); err != nil {
+			zap.L().Warn("error removing world upload", zap.String("file", tmp.Name()), zap.Error(err))
+		}
+	}()
+
+	maxSize := config.GetWorldUploadLimit() * worldExtractedSizeFactor
+	if err := world.Normalize(src, size, format, foundGame.Edition, maxSize, tmp); err != nil {
+		return err
+	}
+
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+
+	// named like a backup so mc-load restores it and it is listed with the game's backups
+	backupName := agones.NewBackupName(foundGame.GetResourceName(), time.Now())
+	if err := storage.Get().Upload(backupName, tmp); err != nil {
+		return err
+	}
+
+	if err := db.DB().RunInTransaction(context.Background(), func(tx *pg.Tx) error {
+		// the game may have been deleted while its world was uploaded
+		if err := getByNameAndUserId(tx, foundGame, name, userId); err != nil {
+			if err == pg.ErrNoRows {
+				return ErrGameServerNotFound
+			}
+			return err
+		}
+
+		return setRestoreBackup(tx, foundGame, backupName)
+	}); err != nil {
+		return err
+	}
+
+	gs, err := agones.Client().GetForUser(foundGame.GetResourceName(), userId)
+	if err != nil && !k8sErrors.IsNotFound(err) {
+		return err
+	}
+
+	game.MergeGame(foundGame, gs)
+
+	return nil
+}
@@ -0,0 +1,29 @@
+package user
+
+import (
+	"github.com/go-pg/pg/v10"
+	"github.com/google/uuid"
+
+	"agones-minecraft/config"
+	userv1Model "agones-minecraft/models/v1/user"
+)
+
+// Gets a user's plan. Users without a plan are on the default plan
+func GetPlanForUser(tx *pg.Tx, plan *userv1Model.Plan, userId uuid.UUID) error {
+	var user userv1Model.User
+	if err := tx.Model(&user).
+		Column("u.plan_id").
+		Where("u.id = ?", userId).
+		First(); err != nil {
+		return err
+	}
+
+	query := tx.Model(plan)
+	if user.PlanID != uuid.Nil {
+		query.Where("id = ?", user.PlanID)
+	} else {
+		query.Where("name = ?", config.GetDefaultPlan())
+	}
+
+	return query.First()
+}
@@ -0,0 +1,171 @@
+package catalog
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	gamev1Model "agones-minecraft/models/v1/game"
+)
+
+var (
+	ErrUnsupportedServerType error = errors.New("unsupported server type")
+)
+
+// Server type not available for an edition
+type ErrUnsupportedEdition struct {
+	Type    gamev1Model.ServerType
+	Edition gamev1Model.Edition
+}
+
+func (e *ErrUnsupportedEdition) Error() string {
+	return fmt.Sprintf("%s servers are not available for %s edition", e.Type, e.Edition)
+}
+
+// Minecraft version not available for a server type
+type ErrUnsupportedVersion struct {
+	Type    gamev1Model.ServerType
+	Version string
+}
+
+func (e *ErrUnsupportedVersion) Error() string {
+	return fmt.Sprintf("%s servers are not available for version %s", e.Type, e.Version)
+}
+
+// Server type available for new games
+type Entry struct {
+	Type gamev1Model.ServerType
+	// TYPE env for itzg/minecraft-server
+	ImageType string
+	Editions  []gamev1Model.Edition
+	// Oldest supported release
+	MinVersion string
+	Snapshots  bool
+}
+
+var snapshotVersion = regexp.MustCompile(`^(SNAPSHOT|\d{2}w\d{2}[a-z])$`)
+
+var entries = map[gamev1Model.ServerType]Entry{
+	gamev1Model.Vanilla: {
+		Type:       gamev1Model.Vanilla,
+		ImageType:  "VANILLA",
+		Editions:   []gamev1Model.Edition{gamev1Model.JavaEdition, gamev1Model.BedrockEdition},
+		MinVersion: "1.0",
+		Snapshots:  true,
+	},
+	gamev1Model.Paper: {
+		Type:       gamev1Model.Paper,
+		ImageType:  "PAPER",
+		Editions:   []gamev1Model.Edition{gamev1Model.JavaEdition},
+		MinVersion: "1.8",
+	},
+	gamev1Model.Spigot: {
+		Type:       gamev1Model.Spigot,
+		ImageType:  "SPIGOT",
+		Editions:   []gamev1Model.Edition{gamev1Model.JavaEdition},
+		MinVersion: "1.8",
+	},
+	gamev1Model.Fabric: {
+		Type:       gamev1Model.Fabric,
+		ImageType:  "FABRIC",
+		Editions:   []gamev1Model.Edition{gamev1Model.JavaEdition},
+		MinVersion: "1.14",
+		Snapshots:  true,
+	},
+	gamev1Model.Forge: {
+		Type:       gamev1Model.Forge,
+		ImageType:  "FORGE",
+		Editions:   []gamev1Model.Edition{gamev1Model.JavaEdition},
+		MinVersion: "1.1",
+	},
+}
+
+// Gets a catalog entry by server type
+func Get(serverType gamev1Model.ServerType) (Entry, bool) {
+	entry, ok := entries[serverType]
+	return entry, ok
+}
+
+// Checks if a server type is in the catalog
+func Exists(serverType gamev1Model.ServerType) bool {
+	_, ok := entries[serverType]
+	return ok
+}
+
+// Lists all catalog entries
+func List() []Entry {
+	list := make([]Entry, 0, len(entries))
+	for _, t := range []gamev1Model.ServerType{gamev1Model.Vanilla, gamev1Model.Paper, gamev1Model.Spigot, gamev1Model.Fabric, gamev1Model.Forge} {
+		list = append(list, entries[t])
+	}
+	return list
+}
+
+// Validates that a server type is available for the edition and Minecraft version
+func Validate(serverType gamev1Model.ServerType, edition gamev1Model.Edition, version string) error {
+	entry, ok := entries[serverType]
+	if !ok {
+		return ErrUnsupportedServerType
+	}
+
+	if !entry.supportsEdition(edition) {
+		return &ErrUnsupportedEdition{serverType, edition}
+	}
+
+	// bedrock versions are not tracked by server type
+	if edition == gamev1Model.BedrockEdition {
+		return nil
+	}
+
+	if snapshotVersion.MatchString(version) {
+		if !entry.Snapshots {
+			return &ErrUnsupportedVersion{serverType, version}
+		}
+		return nil
+	}
+
+	if version == gamev1Model.LatestVersion || version == "PREVIOUS" {
+		return nil
+	}
+
+	if compareVersions(version, entry.MinVersion) < 0 {
+		return &ErrUnsupportedVersion{serverType, version}
+	}
+
+	return nil
+}
+
+func (e Entry) supportsEdition(edition gamev1Model.Edition) bool {
+	for _, ed := range e.Editions {
+		if ed == edition {
+			return true
+		}
+	}
+	return false
+}
+
+// Compares dot separated release versions. Returns -1 if a is older than b, 1 if newer and 0 if equal
+func compareVersions(a, b string) int {
+	aParts := strings.Split(a, ".")
+	bParts := strings.Split(b, ".")
+
+	for i := 0; i < len(aParts) || i < len(bParts); i++ {
+		var aNum, bNum int
+		if i < len(aParts) {
+			aNum, _ = strconv.Atoi(aParts[i])
+		}
+		if i < len(bParts) {
+			bNum, _ = strconv.Atoi(bParts[i])
+		}
+
+		if aNum < bNum {
+			return -1
+		} else if aNum > bNum {
+			return 1
+		}
+	}
+
+	return 0
+}
@@ -3,6 +3,8 @@ package agones
 import (
 	"context"
 	"fmt"
+	"net"
+	"strconv"
 	"time"
 
 	agonesv1 "agones.dev/agones/pkg/apis/agones/v1"
@@ -10,6 +12,7 @@ import (
 	v1Informers "agones.dev/agones/pkg/client/informers/externalversions/agones/v1"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
 	k8sErrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
@@ -19,6 +22,7 @@ import (
 	"k8s.io/client-go/tools/cache"
 
 	k8s "agones-minecraft/services/k8s"
+	"agones-minecraft/services/rcon"
 )
 
 var agonesClient *AgonesClient
@@ -27,11 +31,18 @@ var agonesClient *AgonesClient
 type AgonesClient struct {
 	clientSet *versioned.Clientset
 	informer  v1Informers.GameServerInformer
+	broker    *Broker
 }
 
 // Timeout for connecting to k8s server
 var DefaultTimeout time.Duration = time.Second * 30
 
+// Timeout for one-off world backups
+var DefaultBackupTimeout time.Duration = time.Minute * 2
+
+// Runs a single backup job immediately. Omitting the cron overrides BACKUP_CRON from the container env
+var backupCommand []string = []string{"agones-mc", "backup", "--backup-cron=", "--initial-delay=0s"}
+
 // Initializes Agones client
 func Init() {
 	c, err := New(k8s.GetConfig())
@@ -66,7 +77,21 @@ func New(config *rest.Config) (*AgonesClient, error) {
 	}
 	gameServerInformer := NewGameServerInformer(agonesClient)
 
-	return &AgonesClient{agonesClient, gameServerInformer}, nil
+	broker := NewBroker()
+	gameServerInformer.Informer().AddEventHandler(broker.handlers())
+
+	return &AgonesClient{agonesClient, gameServerInformer, broker}, nil
+}
+
+// Subscribes to changes of a user's GameServers. The returned func must be called to unsubscribe
+func (c *AgonesClient) Subscribe(userId uuid.UUID) (<-chan Event, func()) {
+	return c.broker.Subscribe(userId)
+}
+
+// Adds a handler for GameServer changes. Handlers added after the informer has synced
+// are sent an add for each existing GameServer
+func (c *AgonesClient) AddEventHandler(handler cache.ResourceEventHandler) {
+	c.informer.Informer().AddEventHandler(handler)
 }
 
 func (c *AgonesClient) Ping() error {
@@ -95,12 +120,36 @@ func (c *AgonesClient) Get(serverName string) (*agonesv1.GameServer, error) {
 // If user does not match label on resourece it returns a not found k8s api error
 func (c *AgonesClient) GetForUser(serverName string, userId uuid.UUID) (*agonesv1.GameServer, error) {
 	gs, err := c.Get(serverName)
+	if err != nil {
+		return nil, err
+	}
 
 	if GetUserId(gs) != userId.String() {
 		return nil, k8sErrors.NewNotFound(agonesv1.Resource("GameServer"), serverName)
 	}
 
-	return gs, err
+	return gs, nil
+}
+
+// Gets a game's GameServer by the game's uuid label
+func (c *AgonesClient) GetByUUID(gameId uuid.UUID) (*agonesv1.GameServer, error) {
+	req, err := labels.NewRequirement(UUIDLabel, selection.Equals, []string{gameId.String()})
+	if err != nil {
+		return nil, err
+	}
+
+	gsList, err := c.informer.Lister().
+		GameServers(metav1.NamespaceDefault).
+		List(labels.NewSelector().Add(*req))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(gsList) == 0 {
+		return nil, k8sErrors.NewNotFound(agonesv1.Resource("GameServer"), gameId.String())
+	}
+
+	return gsList[0], nil
 }
 
 // Gets all GameServers for default namespace
@@ -137,6 +186,14 @@ func (c *AgonesClient) CreateDryRun(server *agonesv1.GameServer) (*agonesv1.Game
 		})
 }
 
+// Updates an existing GameServer
+func (c *AgonesClient) Update(server *agonesv1.GameServer) (*agonesv1.GameServer, error) {
+	return c.clientSet.
+		AgonesV1().
+		GameServers(server.Namespace).
+		Update(context.Background(), server, metav1.UpdateOptions{})
+}
+
 // Deletes a GameServer by name
 func (c *AgonesClient) Delete(serverName string) error {
 	return c.clientSet.
@@ -144,3 +201,107 @@ func (c *AgonesClient) Delete(serverName string) error {
 		GameServers(metav1.NamespaceDefault).
 		Delete(context.Background(), serverName, metav1.DeleteOptions{})
 }
+
+// Runs a one-off world backup in the GameServer's mc-backup container
+func (c *AgonesClient) BackupWorld(gs *agonesv1.GameServer) error {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultBackupTimeout)
+	defer cancel()
+
+	_, err := k8s.GetClient().Exec(ctx, gs.Namespace, gs.Name, MCBackupContainerName, backupCommand)
+	return err
+}
+
+// Opens an authenticated RCON connection to the GameServer's pod
+func (c *AgonesClient) RCON(gs *agonesv1.GameServer) (*rcon.Client, error) {
+	podIP, err := k8s.GetClient().GetPodIP(gs.Namespace, gs.Name)
+	if err != nil {
+		return nil, err
+	}
+
+	gameId, err := uuid.Parse(gs.Labels[UUIDLabel])
+	if err != nil {
+		return nil, err
+	}
+
+	secret, err := k8s.GetClient().GetSecret(gs.Namespace, RCONSecretName(gameId))
+	if err != nil {
+		return nil, err
+	}
+
+	address := net.JoinHostPort(podIP, strconv.Itoa(int(DefaultRCONPort)))
+	return rcon.Dial(address, string(secret.Data[RCONSecretKey]), rcon.DefaultTimeout)
+}
+
+// Records a Kubernetes Event about a GameServer
+func (c *AgonesClient) RecordEvent(gs *agonesv1.GameServer, eventType string, reason string, message string) error {
+	_, err := k8s.GetClient().CreateEvent(NewEvent(gs, eventType, reason, message))
+	return err
+}
+
+// Gets the pod of a GameServer. Agones names pods after their GameServer
+func (c *AgonesClient) GetPod(gs *agonesv1.GameServer) (*corev1.Pod, error) {
+	return k8s.GetClient().GetPod(gs.Namespace, gs.Name)
+}
+
+// Returns the message of the latest Warning Event about a GameServer. Agones records why
+// GameServers become Unhealthy or fail as Warning Events. Empty when there is none
+func (c *AgonesClient) GetLatestWarning(gs *agonesv1.GameServer) (string, error) {
+	events, err := k8s.GetClient().ListEvents(gs.Namespace, gs.Name)
+	if err != nil {
+		return "", err
+	}
+
+	var latest *corev1.Event
+	for i := range events {
+		event := &events[i]
+		if event.Type != corev1.EventTypeWarning || event.InvolvedObject.UID != gs.UID {
+			continue
+		}
+		if latest == nil || latest.LastTimestamp.Before(&event.LastTimestamp) {
+			latest = event
+		}
+	}
+
+	if latest == nil {
+		return "", nil
+	}
+
+	return latest.Message, nil
+}
+
+// Creates the RCON password Secret for a new GameServer. The GameServer's containers
+// wait for the Secret before starting
+func (c *AgonesClient) CreateRCONSecret(gs *agonesv1.GameServer) error {
+	secret, err := NewRCONSecret(gs)
+	if err != nil {
+		return err
+	}
+
+	_, err = k8s.GetClient().CreateSecret(secret)
+	if !k8sErrors.IsAlreadyExists(err) {
+		return err
+	}
+
+	// a Secret left by a recently deleted GameServer may not be garbage collected yet
+	existing, err := k8s.GetClient().GetSecret(secret.Namespace, secret.Name)
+	if err != nil {
+		return err
+	}
+
+	// the Secret was created by an earlier attempt for this GameServer, whose pod already uses its password
+	owned := false
+	for _, ref := range existing.OwnerReferences {
+		if ref.UID == gs.UID {
+			owned = true
+			break
+		}
+	}
+
+	existing.OwnerReferences = secret.OwnerReferences
+	if !owned {
+		existing.Data = secret.Data
+	}
+
+	_, err = k8s.GetClient().UpdateSecret(existing)
+	return err
+}
@@ -1,7 +1,12 @@
 package agones
 
 import (
+	"strconv"
+	"strings"
+
 	"agones-minecraft/config"
+	gamev1Model "agones-minecraft/models/v1/game"
+	"agones-minecraft/services/catalog"
 
 	"github.com/google/uuid"
 
@@ -28,19 +33,41 @@ func (d *MCServerDirector) BuildServer(name string, subdomain string, uuid uuid.
 	return d.builder.GetServer()
 }
 
+// Builds a server that loads a world backup before starting. An empty backup name loads the latest backup
+func (d *MCServerDirector) BuildServerWithBackup(name string, subdomain string, uuid uuid.UUID, userId uuid.UUID, backup string) *agonesv1.GameServer {
+	d.builder.SetWorldBackup(backup)
+
+	return d.BuildServer(name, subdomain, uuid, userId)
+}
+
 type MCServerBuilder interface {
 	SetName(string)
 	SetAddress(string)
 	SetUserID(uuid.UUID)
 	SetUUID(uuid.UUID)
+	SetWorldBackup(string)
+	SetWorldVolumeClaim(string)
+	SetSettings(gamev1Model.Settings)
+	SetServerType(gamev1Model.ServerType)
+	SetMods([]*gamev1Model.GameMod)
+	SetWhitelist([]*gamev1Model.GamePlayer)
+	SetOps([]*gamev1Model.GamePlayer)
 	GetServer() *agonesv1.GameServer
 }
 
 type JavaServerBuilder struct {
-	Name    string
-	Address string
-	UserId  uuid.UUID
-	UUID    uuid.UUID
+	Name        string
+	Address     string
+	UserId      uuid.UUID
+	UUID        uuid.UUID
+	LoadWorld   bool
+	WorldBackup string
+	WorldClaim  string
+	Settings    *gamev1Model.Settings
+	ServerType  gamev1Model.ServerType
+	Mods        []*gamev1Model.GameMod
+	Whitelist   []*gamev1Model.GamePlayer
+	Ops         []*gamev1Model.GamePlayer
 }
 
 func NewJavaServerBuilder() *JavaServerBuilder {
@@ -63,12 +90,42 @@ func (j *JavaServerBuilder) SetUUID(uuid uuid.UUID) {
 	j.UUID = uuid
 }
 
+func (j *JavaServerBuilder) SetWorldBackup(backup string) {
+	j.LoadWorld = true
+	j.WorldBackup = backup
+}
+
+func (j *JavaServerBuilder) SetWorldVolumeClaim(claimName string) {
+	j.WorldClaim = claimName
+}
+
+func (j *JavaServerBuilder) SetSettings(settings gamev1Model.Settings) {
+	j.Settings = &settings
+}
+
+func (j *JavaServerBuilder) SetServerType(st gamev1Model.ServerType) {
+	j.ServerType = st
+}
+
+func (j *JavaServerBuilder) SetMods(mods []*gamev1Model.GameMod) {
+	j.Mods = mods
+}
+
+func (j *JavaServerBuilder) SetWhitelist(players []*gamev1Model.GamePlayer) {
+	j.Whitelist = players
+}
+
+func (j *JavaServerBuilder) SetOps(players []*gamev1Model.GamePlayer) {
+	j.Ops = players
+}
+
 func (j *JavaServerBuilder) GetServer() *agonesv1.GameServer {
 	gs := newServer()
 	SetHostname(&gs, config.GetDNSZone(), j.Address)
 	SetName(&gs, j.UserId, j.Name)
 	SetUserId(&gs, j.UserId)
 	SetUUID(&gs, j.UUID)
+	SetRCONSecret(&gs, RCONSecretName(j.UUID))
 	SetEdition(&gs, JavaEdition)
 
 	gs.Annotations[SRVServiceAnnotation] = JavaSRVServiceName
@@ -76,32 +133,56 @@ func (j *JavaServerBuilder) GetServer() *agonesv1.GameServer {
 	gs.Spec.Template.Spec.Containers[0].Image = DefaultJavaImage
 	gs.Spec.Ports[0].ContainerPort = DefaultJavaContainerPort
 
-	for _, c := range gs.Spec.Template.Spec.Containers {
-		if c.Name == "mc-monitor" {
-			c.Env = append(c.Env, corev1.EnvVar{
-				Name: edition, Value: string(JavaEdition),
-			},
-				corev1.EnvVar{
-					Name: port, Value: string(DefaultJavaContainerPort),
-				},
-			)
-		}
+	setSidecarEnv(&gs, JavaEdition, DefaultJavaContainerPort)
 
-		if c.Name == "mc-backup" {
-			c.Env = append(c.Env, corev1.EnvVar{
-				Name: edition, Value: string(JavaEdition),
-			})
-		}
+	if j.Settings != nil {
+		gs.Spec.Template.Spec.Containers[0].Env = append(gs.Spec.Template.Spec.Containers[0].Env, newJavaSettingsEnv(j.Settings)...)
+		SetBackupSchedule(&gs, j.Settings.BackupSettings)
 
+		tier := GetServerTier(j.Settings.Tier)
+		SetServerTier(&gs, tier)
+		gs.Spec.Template.Spec.Containers[0].Env = append(gs.Spec.Template.Spec.Containers[0].Env, newJavaMemoryEnv(tier))
 	}
+
+	if entry, ok := catalog.Get(j.ServerType); ok {
+		gs.Spec.Template.Spec.Containers[0].Env = append(gs.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{Name: serverType, Value: entry.ImageType})
+	}
+
+	gs.Spec.Template.Spec.Containers[0].Env = append(gs.Spec.Template.Spec.Containers[0].Env, newModsEnv(j.Mods)...)
+
+	// setting WHITELIST enforces the whitelist so it is only set when the whitelist is enabled
+	if len(j.Whitelist) > 0 && j.Settings != nil && j.Settings.Whitelist {
+		gs.Spec.Template.Spec.Containers[0].Env = append(gs.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{Name: whitelistEnv, Value: joinPlayerIds(j.Whitelist)})
+	}
+
+	if len(j.Ops) > 0 {
+		gs.Spec.Template.Spec.Containers[0].Env = append(gs.Spec.Template.Spec.Containers[0].Env, corev1.EnvVar{Name: opsEnv, Value: joinPlayerIds(j.Ops)})
+	}
+
+	if j.LoadWorld {
+		SetWorldLoader(&gs, JavaEdition, j.WorldBackup)
+	}
+
+	if j.WorldClaim != "" {
+		SetWorldVolumeClaim(&gs, j.WorldClaim)
+	}
+
 	return &gs
 }
 
 type BedrockServerBuilder struct {
-	Name    string
-	Address string
-	UserId  uuid.UUID
-	UUID    uuid.UUID
+	Name        string
+	Address     string
+	UserId      uuid.UUID
+	UUID        uuid.UUID
+	LoadWorld   bool
+	WorldBackup string
+	WorldClaim  string
+	Settings    *gamev1Model.Settings
+	ServerType  gamev1Model.ServerType
+	Mods        []*gamev1Model.GameMod
+	Whitelist   []*gamev1Model.GamePlayer
+	Ops         []*gamev1Model.GamePlayer
 }
 
 func NewBedrockServerBuilder() *BedrockServerBuilder {
@@ -124,35 +205,195 @@ func (j *BedrockServerBuilder) SetUUID(uuid uuid.UUID) {
 	j.UUID = uuid
 }
 
+func (j *BedrockServerBuilder) SetWorldBackup(backup string) {
+	j.LoadWorld = true
+	j.WorldBackup = backup
+}
+
+func (j *BedrockServerBuilder) SetWorldVolumeClaim(claimName string) {
+	j.WorldClaim = claimName
+}
+
+func (j *BedrockServerBuilder) SetSettings(settings gamev1Model.Settings) {
+	j.Settings = &settings
+}
+
+// Bedrock servers only run the vanilla server
+func (j *BedrockServerBuilder) SetServerType(st gamev1Model.ServerType) {
+	j.ServerType = st
+}
+
+// Bedrock servers do not load mods or plugins
+func (j *BedrockServerBuilder) SetMods(mods []*gamev1Model.GameMod) {
+	j.Mods = mods
+}
+
+// Bedrock whitelists are keyed by Xbox accounts instead of Minecraft accounts
+func (j *BedrockServerBuilder) SetWhitelist(players []*gamev1Model.GamePlayer) {
+	j.Whitelist = players
+}
+
+// Bedrock operators are keyed by Xbox accounts instead of Minecraft accounts
+func (j *BedrockServerBuilder) SetOps(players []*gamev1Model.GamePlayer) {
+	j.Ops = players
+}
+
 func (j *BedrockServerBuilder) GetServer() *agonesv1.GameServer {
 	gs := newServer()
 	SetHostname(&gs, config.GetDNSZone(), j.Address)
 	SetName(&gs, j.UserId, j.Name)
 	SetUserId(&gs, j.UserId)
 	SetUUID(&gs, j.UUID)
+	SetRCONSecret(&gs, RCONSecretName(j.UUID))
 	SetEdition(&gs, BedrockEdition)
 
 	gs.Spec.Ports[0].Protocol = corev1.ProtocolUDP
 	gs.Spec.Template.Spec.Containers[0].Image = DefaultBedrockImage
 	gs.Spec.Ports[0].ContainerPort = DefaultBedrockContainerPort
 
-	for _, c := range gs.Spec.Template.Spec.Containers {
-		if c.Name == "mc-monitor" {
-			c.Env = append(c.Env, corev1.EnvVar{
-				Name: edition, Value: string(BedrockEdition),
-			},
-				corev1.EnvVar{
-					Name: port, Value: string(DefaultBedrockContainerPort),
-				},
+	setSidecarEnv(&gs, BedrockEdition, DefaultBedrockContainerPort)
+
+	if j.Settings != nil {
+		gs.Spec.Template.Spec.Containers[0].Env = append(gs.Spec.Template.Spec.Containers[0].Env, newBedrockSettingsEnv(j.Settings)...)
+		SetBackupSchedule(&gs, j.Settings.BackupSettings)
+		SetServerTier(&gs, GetServerTier(j.Settings.Tier))
+	}
+
+	if j.LoadWorld {
+		SetWorldLoader(&gs, BedrockEdition, j.WorldBackup)
+	}
+
+	if j.WorldClaim != "" {
+		SetWorldVolumeClaim(&gs, j.WorldClaim)
+	}
+
+	return &gs
+}
+
+// Sets edition and port env for sidecar containers
+func setSidecarEnv(gs *agonesv1.GameServer, ed Edition, containerPort int32) {
+	containers := gs.Spec.Template.Spec.Containers
+
+	for i := range containers {
+		switch containers[i].Name {
+		case MCMonitorContainerName:
+			containers[i].Env = append(containers[i].Env,
+				corev1.EnvVar{Name: edition, Value: string(ed)},
+				corev1.EnvVar{Name: port, Value: strconv.Itoa(int(containerPort))},
 			)
+		case MCBackupContainerName:
+			containers[i].Env = append(containers[i].Env, corev1.EnvVar{Name: edition, Value: string(ed)})
 		}
+	}
+}
+
+// Adds an init container that loads a world backup into the data volume.
+// An empty backup name loads the latest backup for the GameServer
+func SetWorldLoader(gs *agonesv1.GameServer, ed Edition, backup string) {
+	gs.Spec.Template.Spec.InitContainers = append(gs.Spec.Template.Spec.InitContainers, corev1.Container{
+		Name:  MCLoadContainerName,
+		Image: MCLoadImageName,
+		Args:  []string{"load"},
+		Env: []corev1.EnvVar{
+			{
+				Name: podName, ValueFrom: &corev1.EnvVarSource{
+					FieldRef: &corev1.ObjectFieldSelector{
+						FieldPath: "metadata.name",
+					},
+				},
+			},
+			{Name: edition, Value: string(ed)},
+			{Name: bucketName, Value: config.GetBucketName()},
+			{Name: backupName, Value: backup},
+		},
+		ImagePullPolicy: corev1.PullAlways,
+		Resources:       *sidecarResources.DeepCopy(),
+		VolumeMounts: []corev1.VolumeMount{
+			{
+				MountPath: DefaultDataDirectory,
+				Name:      DefaultDataVolumeName,
+			},
+		},
+	})
+}
+
+// Returns itzg/minecraft-server env for game settings
+func newJavaSettingsEnv(settings *gamev1Model.Settings) []corev1.EnvVar {
+	env := []corev1.EnvVar{
+		{Name: "MAX_PLAYERS", Value: strconv.Itoa(settings.Slots)},
+		{Name: "DIFFICULTY", Value: string(settings.Difficulty)},
+		{Name: "MODE", Value: string(settings.GameMode)},
+		{Name: "VERSION", Value: settings.Version},
+		{Name: "LEVEL_TYPE", Value: strings.ToUpper(string(settings.LevelType))},
+		{Name: "ENABLE_WHITELIST", Value: strconv.FormatBool(settings.Whitelist)},
+	}
 
-		if c.Name == "mc-backup" {
-			c.Env = append(c.Env, corev1.EnvVar{
-				Name: edition, Value: string(BedrockEdition),
-			})
+	if settings.MOTD != "" {
+		env = append(env, corev1.EnvVar{Name: "MOTD", Value: settings.MOTD})
+	}
+	if settings.Seed != "" {
+		env = append(env, corev1.EnvVar{Name: "SEED", Value: settings.Seed})
+	}
+
+	return env
+}
+
+// Returns bedrock server env for game settings.
+// Bedrock has no spectator mode or large biome and amplified worlds so they are left to the server defaults
+func newBedrockSettingsEnv(settings *gamev1Model.Settings) []corev1.EnvVar {
+	env := []corev1.EnvVar{
+		{Name: "MAX_PLAYERS", Value: strconv.Itoa(settings.Slots)},
+		{Name: "DIFFICULTY", Value: string(settings.Difficulty)},
+		{Name: "VERSION", Value: settings.Version},
+		{Name: "WHITE_LIST", Value: strconv.FormatBool(settings.Whitelist)},
+	}
+
+	if settings.GameMode != gamev1Model.Spectator {
+		env = append(env, corev1.EnvVar{Name: "GAMEMODE", Value: string(settings.GameMode)})
+	}
+	if settings.LevelType == gamev1Model.DefaultLevel || settings.LevelType == gamev1Model.FlatLevel {
+		env = append(env, corev1.EnvVar{Name: "LEVEL_TYPE", Value: strings.ToUpper(string(settings.LevelType))})
+	}
+	if settings.MOTD != "" {
+		env = append(env, corev1.EnvVar{Name: "SERVER_NAME", Value: settings.MOTD})
+	}
+	if settings.Seed != "" {
+		env = append(env, corev1.EnvVar{Name: "LEVEL_SEED", Value: settings.Seed})
+	}
+
+	return env
+}
+
+// Returns itzg/minecraft-server MODS and PLUGINS env with comma separated download URLs
+func newModsEnv(mods []*gamev1Model.GameMod) []corev1.EnvVar {
+	var modURLs, pluginURLs []string
+
+	for _, mod := range mods {
+		switch mod.Kind {
+		case gamev1Model.ModContent:
+			modURLs = append(modURLs, mod.URL)
+		case gamev1Model.PluginContent:
+			pluginURLs = append(pluginURLs, mod.URL)
 		}
+	}
+
+	var env []corev1.EnvVar
 
+	if len(modURLs) > 0 {
+		env = append(env, corev1.EnvVar{Name: modsEnv, Value: strings.Join(modURLs, ",")})
 	}
-	return &gs
+	if len(pluginURLs) > 0 {
+		env = append(env, corev1.EnvVar{Name: pluginsEnv, Value: strings.Join(pluginURLs, ",")})
+	}
+
+	return env
+}
+
+// Returns comma separated Minecraft UUIDs
+func joinPlayerIds(players []*gamev1Model.GamePlayer) string {
+	ids := make([]string, 0, len(players))
+	for _, player := range players {
+		ids = append(ids, player.MCID.String())
+	}
+	return strings.Join(ids, ",")
 }
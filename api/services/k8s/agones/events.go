@@ -0,0 +1,137 @@
+package agones
+
+import (
+	"sync"
+
+	agonesv1 "agones.dev/agones/pkg/apis/agones/v1"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"k8s.io/client-go/tools/cache"
+)
+
+type EventType string
+
+const (
+	GameServerAdded   EventType = "added"
+	GameServerUpdated EventType = "updated"
+	GameServerDeleted EventType = "deleted"
+
+	// Events buffered per subscriber before events are dropped for slow subscribers
+	subscriberBufferSize int = 32
+)
+
+// GameServer change observed by the informer
+type Event struct {
+	Type       EventType
+	GameServer *agonesv1.GameServer
+}
+
+// Fans out GameServer informer events to subscribers by the userId label
+type Broker struct {
+	mu          sync.RWMutex
+	subscribers map[string]map[chan Event]struct{}
+}
+
+func NewBroker() *Broker {
+	return &Broker{
+		subscribers: map[string]map[chan Event]struct{}{},
+	}
+}
+
+// Subscribes to events for a user's GameServers. The returned func unsubscribes and closes the channel
+func (b *Broker) Subscribe(userId uuid.UUID) (<-chan Event, func()) {
+	key := userId.String()
+	ch := make(chan Event, subscriberBufferSize)
+
+	b.mu.Lock()
+	if b.subscribers[key] == nil {
+		b.subscribers[key] = map[chan Event]struct{}{}
+	}
+	b.subscribers[key][ch] = struct{}{}
+	b.mu.Unlock()
+
+	var once sync.Once
+
+	return ch, func() {
+		once.Do(func() {
+			b.mu.Lock()
+			defer b.mu.Unlock()
+
+			delete(b.subscribers[key], ch)
+			if len(b.subscribers[key]) == 0 {
+				delete(b.subscribers, key)
+			}
+			close(ch)
+		})
+	}
+}
+
+func (b *Broker) publish(eventType EventType, gs *agonesv1.GameServer) {
+	userId := GetUserId(gs)
+	if userId == "" {
+		return
+	}
+
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+
+	for ch := range b.subscribers[userId] {
+		select {
+		case ch <- Event{Type: eventType, GameServer: gs}:
+		default:
+			zap.L().Warn("dropping game server event for slow subscriber", zap.String("gameserver", gs.Name))
+		}
+	}
+}
+
+// Informer handlers publishing GameServer changes. Updates that do not change
+// a GameServer's state, address or ports (e.g. resyncs) are not published
+func (b *Broker) handlers() cache.ResourceEventHandlerFuncs {
+	return cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			if gs, ok := obj.(*agonesv1.GameServer); ok {
+				b.publish(GameServerAdded, gs)
+			}
+		},
+		UpdateFunc: func(oldObj, newObj interface{}) {
+			oldGs, ok := oldObj.(*agonesv1.GameServer)
+			if !ok {
+				return
+			}
+			newGs, ok := newObj.(*agonesv1.GameServer)
+			if !ok {
+				return
+			}
+			if statusChanged(oldGs, newGs) {
+				b.publish(GameServerUpdated, newGs)
+			}
+		},
+		DeleteFunc: func(obj interface{}) {
+			// deletes missed while disconnected from the api server are delivered as tombstones
+			if tombstone, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = tombstone.Obj
+			}
+			if gs, ok := obj.(*agonesv1.GameServer); ok {
+				b.publish(GameServerDeleted, gs)
+			}
+		},
+	}
+}
+
+func statusChanged(oldGs, newGs *agonesv1.GameServer) bool {
+	if oldGs.Status.State != newGs.Status.State || oldGs.Status.Address != newGs.Status.Address {
+		return true
+	}
+
+	if len(oldGs.Status.Ports) != len(newGs.Status.Ports) {
+		return true
+	}
+
+	for i := range oldGs.Status.Ports {
+		if oldGs.Status.Ports[i].Port != newGs.Status.Ports[i].Port {
+			return true
+		}
+	}
+
+	return GetAddress(oldGs) != GetAddress(newGs)
+}
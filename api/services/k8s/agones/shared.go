@@ -1,13 +1,18 @@
 package agones
 
 import (
+	"crypto/rand"
+	"encoding/base64"
 	"fmt"
+	"net"
+	"strconv"
 	"strings"
 	"time"
 
 	agonesv1 "agones.dev/agones/pkg/apis/agones/v1"
 	"github.com/google/uuid"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	"agones-minecraft/config"
@@ -42,21 +47,37 @@ const (
 	// Pod Template
 
 	DefaultRCONPort      int32  = 25575
-	DefaultRCONPassword  string = "minecraft"
 	DefaultDataDirectory string = "/data"
 
+	// rcon
+
+	RCONSecretNamePrefix string = "rcon-"
+	RCONSecretKey        string = "rcon-password"
+	rconPasswordBytes    int    = 24
+
 	// mc-monitor
 
-	MCMonitorImageName string = "saulmaldonado/agones-mc"
+	MCMonitorContainerName string = "mc-monitor"
+	MCMonitorImageName     string = "saulmaldonado/agones-mc"
 
 	// mc-backup
 
-	MCBackupImageName   string = "saulmaldonado/agones-mc"
-	DefaultMCBackupCron string = "0 */6 * * *"
+	MCBackupContainerName string = "mc-backup"
+	MCBackupImageName     string = "saulmaldonado/agones-mc"
+	DefaultMCBackupCron   string = "0 */6 * * *"
+	backupExtension       string = ".zip"
+	// February 31st never occurs so mc-backup stays up for on-demand backups without scheduling any
+	disabledBackupCron string = "0 0 31 2 *"
+
+	// mc-load
+
+	MCLoadContainerName string = "mc-load"
+	MCLoadImageName     string = "saulmaldonado/agones-mc"
 
 	// volumes
 
-	DefaultDataVolumeName string = "world-vol"
+	DefaultDataVolumeName      string = "world-vol"
+	WorldVolumeClaimNamePrefix string = "world-"
 
 	// annotations
 
@@ -66,9 +87,16 @@ const (
 
 	// labels
 
-	EditionLabel string = "edition"
-	UserIdLabel  string = "userId"
-	UUIDLabel    string = "uuid"
+	EditionLabel  string = "edition"
+	UserIdLabel   string = "userId"
+	UUIDLabel     string = "uuid"
+	OrphanedLabel string = "orphaned"
+
+	// events
+
+	EventSource        string = "agones-minecraft-api"
+	IdleShutdownReason string = "IdleShutdown"
+	OrphanedReason     string = "Orphaned"
 
 	// env names
 
@@ -84,9 +112,28 @@ const (
 	rconPort     string = "RCON_PORT"
 	backupCron   string = "BACKUP_CRON"
 	bucketName   string = "BUCKET_NAME"
+	backupName   string = "BACKUP_NAME"
+	serverType   string = "TYPE"
+	modsEnv      string = "MODS"
+	pluginsEnv   string = "PLUGINS"
+	whitelistEnv string = "WHITELIST"
+	opsEnv       string = "OPS"
+	memoryEnv    string = "MEMORY"
 )
 
 var (
+	// sidecar and init container resources
+
+	sidecarResources corev1.ResourceRequirements = corev1.ResourceRequirements{
+		Requests: corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("50m"),
+			corev1.ResourceMemory: resource.MustParse("64Mi"),
+		},
+		Limits: corev1.ResourceList{
+			corev1.ResourceMemory: resource.MustParse("256Mi"),
+		},
+	}
+
 	// health
 
 	DefaultInitialDelay     time.Duration = time.Second * (2)
@@ -114,6 +161,11 @@ func SetHostname(gs *agonesv1.GameServer, domain, subdomain string) {
 	gs.SetAnnotations(anno)
 }
 
+// Returns the subdomain of a game address in the DNS zone
+func GetSubdomainFromAddress(address string) string {
+	return strings.TrimSuffix(address, "."+config.GetDNSZone())
+}
+
 func GetDomainName(gs *agonesv1.GameServer) string {
 	return gs.Annotations[HostnameAnnotation]
 }
@@ -137,11 +189,12 @@ func SetName(gs *agonesv1.GameServer, userId uuid.UUID, name string) {
 }
 
 func GetName(gs *agonesv1.GameServer) string {
-	return strings.TrimPrefix(strings.TrimLeft(gs.Name, "0123456789abcdef-"), ".")
+	return strings.TrimPrefix(gs.Name, GetUserId(gs)+".")
 }
 
+// Returns the game state of a GameServer. Games without a GameServer are Off
 func GetState(gs *agonesv1.GameServer) gamev1Model.GameState {
-	if IsOnline(gs) || IsStarting(gs) {
+	if gs != nil && (IsOnline(gs) || IsStarting(gs)) {
 		return gamev1Model.On
 	}
 	return gamev1Model.Off
@@ -186,6 +239,14 @@ func GetPort(gs *agonesv1.GameServer) *int32 {
 	return &gs.Status.Ports[0].Port
 }
 
+// Returns the node address and port players connect to. Empty until the GameServer's pod is scheduled
+func GetServerAddress(gs *agonesv1.GameServer) string {
+	if gs.Status.Address == "" || len(gs.Status.Ports) == 0 {
+		return ""
+	}
+	return net.JoinHostPort(gs.Status.Address, strconv.Itoa(int(gs.Status.Ports[0].Port)))
+}
+
 func IsStarting(gs *agonesv1.GameServer) bool {
 	state := gs.Status.State
 	return IsBeforePodCreated(gs) ||
@@ -205,6 +266,164 @@ func IsBeforePodCreated(gs *agonesv1.GameServer) bool {
 		state == agonesv1.GameServerStateStarting
 }
 
+// Returns the name of a game's persistent world volume claim
+func WorldVolumeClaimName(gameId uuid.UUID) string {
+	return WorldVolumeClaimNamePrefix + gameId.String()
+}
+
+// Returns a new PersistentVolumeClaim for a game's world. The claim is not owned by the GameServer
+// so that it outlives stopped and rescheduled GameServers
+func NewWorldVolumeClaim(gameId uuid.UUID, userId uuid.UUID) (*corev1.PersistentVolumeClaim, error) {
+	storageConfig := config.GetWorldStorageConfig()
+
+	size, err := resource.ParseQuantity(storageConfig.Size)
+	if err != nil {
+		return nil, err
+	}
+
+	claim := &corev1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      WorldVolumeClaimName(gameId),
+			Namespace: metav1.NamespaceDefault,
+			Labels: map[string]string{
+				UserIdLabel: userId.String(),
+				UUIDLabel:   gameId.String(),
+			},
+		},
+		Spec: corev1.PersistentVolumeClaimSpec{
+			AccessModes: []corev1.PersistentVolumeAccessMode{corev1.ReadWriteOnce},
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceStorage: size},
+			},
+		},
+	}
+
+	if storageConfig.StorageClass != "" {
+		claim.Spec.StorageClassName = &storageConfig.StorageClass
+	}
+
+	return claim, nil
+}
+
+// Replaces the GameServer's world volume with a persistent volume claim
+func SetWorldVolumeClaim(gs *agonesv1.GameServer, claimName string) {
+	volumes := gs.Spec.Template.Spec.Volumes
+
+	for i := range volumes {
+		if volumes[i].Name == DefaultDataVolumeName {
+			volumes[i].VolumeSource = corev1.VolumeSource{
+				PersistentVolumeClaim: &corev1.PersistentVolumeClaimVolumeSource{ClaimName: claimName},
+			}
+		}
+	}
+}
+
+// Sets the mc-backup container's schedule
+func SetBackupSchedule(gs *agonesv1.GameServer, settings gamev1Model.BackupSettings) {
+	schedule := settings.BackupCron
+	if !settings.BackupEnabled || schedule == "" {
+		schedule = disabledBackupCron
+	}
+
+	containers := gs.Spec.Template.Spec.Containers
+
+	for i := range containers {
+		if containers[i].Name != MCBackupContainerName {
+			continue
+		}
+		for j := range containers[i].Env {
+			if containers[i].Env[j].Name == backupCron {
+				containers[i].Env[j].Value = schedule
+			}
+		}
+	}
+}
+
+// Returns the prefix of a game's world backups. mc-backup names backups after the GameServer's pod
+func BackupPrefix(resourceName string) string {
+	return resourceName + "-"
+}
+
+// Returns the name of a world backup of a game taken at t, named like mc-backup's backups
+func NewBackupName(resourceName string, t time.Time) string {
+	return BackupPrefix(resourceName) + t.UTC().Format(time.RFC3339) + backupExtension
+}
+
+// Returns when a world backup named <GameServer name>-<RFC3339 time>.zip was taken.
+// Returns false for objects that are not backups of the game
+func ParseBackupTime(resourceName string, backup string) (time.Time, bool) {
+	if !strings.HasPrefix(backup, BackupPrefix(resourceName)) || !strings.HasSuffix(backup, backupExtension) {
+		return time.Time{}, false
+	}
+
+	timestamp := strings.TrimSuffix(strings.TrimPrefix(backup, BackupPrefix(resourceName)), backupExtension)
+
+	t, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return t, true
+}
+
+// Returns the name of a game's RCON password Secret
+func RCONSecretName(gameId uuid.UUID) string {
+	return RCONSecretNamePrefix + gameId.String()
+}
+
+// Returns a new Secret with a random RCON password for the GameServer. The Secret is owned by the GameServer
+// so a new password is generated every time the game is started
+func NewRCONSecret(gs *agonesv1.GameServer) (*corev1.Secret, error) {
+	b := make([]byte, rconPasswordBytes)
+	if _, err := rand.Read(b); err != nil {
+		return nil, err
+	}
+
+	gameId, err := uuid.Parse(gs.Labels[UUIDLabel])
+	if err != nil {
+		return nil, err
+	}
+
+	return &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      RCONSecretName(gameId),
+			Namespace: gs.Namespace,
+			Labels: map[string]string{
+				UserIdLabel: gs.Labels[UserIdLabel],
+				UUIDLabel:   gs.Labels[UUIDLabel],
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(gs, agonesv1.SchemeGroupVersion.WithKind("GameServer")),
+			},
+		},
+		Type: corev1.SecretTypeOpaque,
+		Data: map[string][]byte{
+			RCONSecretKey: []byte(base64.RawURLEncoding.EncodeToString(b)),
+		},
+	}, nil
+}
+
+// Sets the RCON password of the server and backup containers from the game's RCON Secret
+func SetRCONSecret(gs *agonesv1.GameServer, secretName string) {
+	env := corev1.EnvVar{
+		Name: rconPassword,
+		ValueFrom: &corev1.EnvVarSource{
+			SecretKeyRef: &corev1.SecretKeySelector{
+				LocalObjectReference: corev1.LocalObjectReference{Name: secretName},
+				Key:                  RCONSecretKey,
+			},
+		},
+	}
+
+	containers := gs.Spec.Template.Spec.Containers
+
+	for i := range containers {
+		if containers[i].Name == DefaultGameServerContainerName || containers[i].Name == MCBackupContainerName {
+			containers[i].Env = append(containers[i].Env, env)
+		}
+	}
+}
+
 func newServer() agonesv1.GameServer {
 	return agonesv1.GameServer{
 		ObjectMeta: metav1.ObjectMeta{
@@ -240,21 +459,22 @@ func newServer() agonesv1.GameServer {
 							},
 						},
 						{
-							Name:  "mc-monitor",
+							Name:  MCMonitorContainerName,
 							Image: MCMonitorImageName,
 							Args:  []string{"monitor"},
 
 							Env: []corev1.EnvVar{
-								{Name: maxAttempts, Value: string(DefaultFailureThreshold)},
+								{Name: maxAttempts, Value: strconv.Itoa(int(DefaultFailureThreshold))},
 								{Name: initialDelay, Value: DefaultInitialDelay.String()},
 								{Name: interval, Value: DefaultHealthInterval.String()},
 								{Name: timeout, Value: DefaultTimeoutDuration.String()},
 							},
 
 							ImagePullPolicy: corev1.PullAlways,
+							Resources:       *sidecarResources.DeepCopy(),
 						},
 						{
-							Name:  "mc-backup",
+							Name:  MCBackupContainerName,
 							Image: MCMonitorImageName,
 							Args:  []string{"backup"},
 							Env: []corev1.EnvVar{
@@ -265,15 +485,12 @@ func newServer() agonesv1.GameServer {
 										},
 									},
 								},
-								{
-									Name:  rconPassword,
-									Value: DefaultRCONPassword,
-								},
 								{Name: initialDelay, Value: DefaultInitialDelay.String()},
 								{Name: backupCron, Value: DefaultMCBackupCron},
 								{Name: bucketName, Value: config.GetBucketName()},
 							},
 							ImagePullPolicy: corev1.PullAlways,
+							Resources:       *sidecarResources.DeepCopy(),
 							VolumeMounts: []corev1.VolumeMount{
 								{
 									MountPath: DefaultDataDirectory,
@@ -296,3 +513,66 @@ func newServer() agonesv1.GameServer {
 	}
 
 }
+
+// Returns a tier by name. Unknown tiers, such as tiers removed from config, fall back to the default tier
+func GetServerTier(name string) *config.ServerTier {
+	if tier, ok := config.GetServerTier(name); ok {
+		return tier
+	}
+	tier, _ := config.GetServerTier(config.DefaultServerTier)
+	return tier
+}
+
+// Sets the server container's requests and limits from a tier. Memory requests equal limits so that
+// servers are not packed onto nodes that cannot fit their full memory
+func SetServerTier(gs *agonesv1.GameServer, tier *config.ServerTier) {
+	memory := resource.MustParse(fmt.Sprintf("%dMi", tier.Memory))
+
+	containers := gs.Spec.Template.Spec.Containers
+	for i := range containers {
+		if containers[i].Name == DefaultGameServerContainerName {
+			containers[i].Resources = corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse(tier.CPU),
+					corev1.ResourceMemory: memory,
+				},
+				Limits: corev1.ResourceList{
+					corev1.ResourceCPU:    resource.MustParse(tier.CPULimit),
+					corev1.ResourceMemory: memory,
+				},
+			}
+		}
+	}
+}
+
+// Returns itzg/minecraft-server env sizing the Java heap for a tier
+func newJavaMemoryEnv(tier *config.ServerTier) corev1.EnvVar {
+	return corev1.EnvVar{Name: memoryEnv, Value: fmt.Sprintf("%dM", tier.Heap)}
+}
+
+// Returns a new Event about a GameServer
+func NewEvent(gs *agonesv1.GameServer, eventType string, reason string, message string) *corev1.Event {
+	now := metav1.Now()
+
+	return &corev1.Event{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: gs.Name + ".",
+			Namespace:    gs.Namespace,
+		},
+		InvolvedObject: corev1.ObjectReference{
+			APIVersion:      agonesv1.SchemeGroupVersion.String(),
+			Kind:            "GameServer",
+			Name:            gs.Name,
+			Namespace:       gs.Namespace,
+			UID:             gs.UID,
+			ResourceVersion: gs.ResourceVersion,
+		},
+		Reason:         reason,
+		Message:        message,
+		Type:           eventType,
+		Source:         corev1.EventSource{Component: EventSource},
+		FirstTimestamp: now,
+		LastTimestamp:  now,
+		Count:          1,
+	}
+}
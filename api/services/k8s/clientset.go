@@ -0,0 +1,170 @@
+package k8s
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/remotecommand"
+	"k8s.io/client-go/transport/spdy"
+)
+
+var client *Client
+
+// Kubernetes clientset wrapper
+type Client struct {
+	clientSet *kubernetes.Clientset
+	config    *rest.Config
+}
+
+// Initializes kubernetes client. Requires k8s config to be initialized
+func InitClient() {
+	c, err := NewClient(GetConfig())
+	if err != nil {
+		zap.L().Fatal("error initializing k8s client", zap.Error(err))
+	}
+	client = c
+}
+
+// Gets initialized kubernetes client
+func GetClient() *Client {
+	return client
+}
+
+// Creates new kubernetes client
+func NewClient(config *rest.Config) (*Client, error) {
+	clientSet, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Client{clientSet, config}, nil
+}
+
+// Error for commands that exited with a non-zero exit code
+type ErrExecFailed struct {
+	Command []string
+	Stderr  string
+	error
+}
+
+func (e *ErrExecFailed) Error() string {
+	return fmt.Sprintf("command %v failed: %s: %s", e.Command, e.error, e.Stderr)
+}
+
+func (e *ErrExecFailed) Unwrap() error {
+	return e.error
+}
+
+// Executes a command in a pod container and returns its stdout
+func (c *Client) Exec(ctx context.Context, namespace, podName, container string, command []string) (string, error) {
+	req := c.clientSet.CoreV1().RESTClient().
+		Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(podName).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Container: container,
+			Command:   command,
+			Stdout:    true,
+			Stderr:    true,
+		}, scheme.ParameterCodec)
+
+	transport, upgrader, err := spdy.RoundTripperFor(c.config)
+	if err != nil {
+		return "", err
+	}
+
+	conn := &closableUpgrader{Upgrader: upgrader}
+
+	exec, err := remotecommand.NewSPDYExecutorForTransports(transport, conn, "POST", req.URL())
+	if err != nil {
+		return "", err
+	}
+
+	var stdout, stderr bytes.Buffer
+
+	done := make(chan error, 1)
+	go func() {
+		done <- exec.Stream(remotecommand.StreamOptions{Stdout: &stdout, Stderr: &stderr})
+	}()
+
+	select {
+	case err := <-done:
+		if err != nil {
+			return stdout.String(), &ErrExecFailed{command, stderr.String(), err}
+		}
+		return stdout.String(), nil
+	case <-ctx.Done():
+		// closing the connection ends the stream and its goroutine
+		conn.Close()
+		return "", ctx.Err()
+	}
+}
+
+// Upgrader keeping the connection of an exec stream so that the stream can be closed when its context is done
+type closableUpgrader struct {
+	spdy.Upgrader
+	mu     sync.Mutex
+	conn   httpstream.Connection
+	closed bool
+}
+
+func (u *closableUpgrader) NewConnection(resp *http.Response) (httpstream.Connection, error) {
+	conn, err := u.Upgrader.NewConnection(resp)
+	if err != nil {
+		return nil, err
+	}
+
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	// closed before the connection was upgraded
+	if u.closed {
+		conn.Close()
+		return nil, context.Canceled
+	}
+
+	u.conn = conn
+	return conn, nil
+}
+
+// Closes the upgraded connection, or the connection once it is upgraded
+func (u *closableUpgrader) Close() {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.closed = true
+	if u.conn != nil {
+		u.conn.Close()
+	}
+}
+
+// Gets a pod by name
+func (c *Client) GetPod(namespace, podName string) (*corev1.Pod, error) {
+	return c.clientSet.CoreV1().Pods(namespace).Get(context.Background(), podName, metav1.GetOptions{})
+}
+
+// Gets the IP of a running pod
+func (c *Client) GetPodIP(namespace, podName string) (string, error) {
+	pod, err := c.clientSet.CoreV1().Pods(namespace).Get(context.Background(), podName, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+
+	if pod.Status.PodIP == "" {
+		return "", fmt.Errorf("pod %s has no IP", podName)
+	}
+
+	return pod.Status.PodIP, nil
+}
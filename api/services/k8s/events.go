@@ -0,0 +1,31 @@
+package k8s
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+// Creates a new Event
+func (c *Client) CreateEvent(event *corev1.Event) (*corev1.Event, error) {
+	return c.clientSet.
+		CoreV1().
+		Events(event.Namespace).
+		Create(context.Background(), event, metav1.CreateOptions{})
+}
+
+// Lists Events about an object by name
+func (c *Client) ListEvents(namespace, name string) ([]corev1.Event, error) {
+	list, err := c.clientSet.
+		CoreV1().
+		Events(namespace).
+		List(context.Background(), metav1.ListOptions{
+			FieldSelector: fields.OneTermEqualSelector("involvedObject.name", name).String(),
+		})
+	if err != nil {
+		return nil, err
+	}
+	return list.Items, nil
+}
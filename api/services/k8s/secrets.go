@@ -0,0 +1,32 @@
+package k8s
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Creates a new Secret
+func (c *Client) CreateSecret(secret *corev1.Secret) (*corev1.Secret, error) {
+	return c.clientSet.
+		CoreV1().
+		Secrets(secret.Namespace).
+		Create(context.Background(), secret, metav1.CreateOptions{})
+}
+
+// Updates an existing Secret
+func (c *Client) UpdateSecret(secret *corev1.Secret) (*corev1.Secret, error) {
+	return c.clientSet.
+		CoreV1().
+		Secrets(secret.Namespace).
+		Update(context.Background(), secret, metav1.UpdateOptions{})
+}
+
+// Gets a Secret by name
+func (c *Client) GetSecret(namespace, name string) (*corev1.Secret, error) {
+	return c.clientSet.
+		CoreV1().
+		Secrets(namespace).
+		Get(context.Background(), name, metav1.GetOptions{})
+}
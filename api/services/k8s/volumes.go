@@ -0,0 +1,24 @@
+package k8s
+
+import (
+	"context"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Creates a new PersistentVolumeClaim
+func (c *Client) CreateVolumeClaim(claim *corev1.PersistentVolumeClaim) (*corev1.PersistentVolumeClaim, error) {
+	return c.clientSet.
+		CoreV1().
+		PersistentVolumeClaims(claim.Namespace).
+		Create(context.Background(), claim, metav1.CreateOptions{})
+}
+
+// Deletes a PersistentVolumeClaim by name
+func (c *Client) DeleteVolumeClaim(namespace, name string) error {
+	return c.clientSet.
+		CoreV1().
+		PersistentVolumeClaims(namespace).
+		Delete(context.Background(), name, metav1.DeleteOptions{})
+}
@@ -0,0 +1,36 @@
+package mods
+
+import (
+	gamev1Model "agones-minecraft/models/v1/game"
+)
+
+// Local fetcher that resolves projects from a fixed set of artifacts. Stands in for remote fetchers in tests
+type FakeFetcher struct {
+	Artifacts map[string]Artifact
+	// Minecraft versions each project has files for. Projects without versions have files for every version
+	Versions map[string][]string
+}
+
+func NewFakeFetcher(artifacts map[string]Artifact) *FakeFetcher {
+	return &FakeFetcher{Artifacts: artifacts}
+}
+
+func (f *FakeFetcher) Fetch(projectId string, serverType gamev1Model.ServerType, version string) (*Artifact, error) {
+	artifact, ok := f.Artifacts[projectId]
+	if !ok {
+		return nil, ErrModNotFound
+	}
+
+	versions, ok := f.Versions[projectId]
+	if !ok {
+		return &artifact, nil
+	}
+
+	for _, v := range versions {
+		if v == version {
+			return &artifact, nil
+		}
+	}
+
+	return nil, ErrNoCompatibleFile
+}
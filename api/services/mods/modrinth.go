@@ -0,0 +1,100 @@
+package mods
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	gamev1Model "agones-minecraft/models/v1/game"
+)
+
+const (
+	ModrinthEndpoint = "https://api.modrinth.com/v2"
+)
+
+// Resolves Modrinth project IDs or slugs
+type ModrinthFetcher struct {
+	client *http.Client
+}
+
+func NewModrinthFetcher(client *http.Client) *ModrinthFetcher {
+	return &ModrinthFetcher{client}
+}
+
+type modrinthVersion struct {
+	ID            string   `json:"id"`
+	ProjectID     string   `json:"project_id"`
+	Name          string   `json:"name"`
+	VersionNumber string   `json:"version_number"`
+	Loaders       []string `json:"loaders"`
+	Files         []struct {
+		URL     string `json:"url"`
+		Primary bool   `json:"primary"`
+	} `json:"files"`
+}
+
+func (f *ModrinthFetcher) Fetch(projectId string, serverType gamev1Model.ServerType, version string) (*Artifact, error) {
+	query := url.Values{}
+	query.Set("loaders", fmt.Sprintf(`["%s"]`, serverType))
+	switch version {
+	case gamev1Model.LatestVersion:
+		// newest files for any game version
+	case "SNAPSHOT", "PREVIOUS":
+		// resolved by the server image at startup so the game version is not known
+		return nil, ErrUnpinnedVersion
+	default:
+		query.Set("game_versions", fmt.Sprintf(`["%s"]`, version))
+	}
+
+	endpoint := fmt.Sprintf("%s/project/%s/version?%s", ModrinthEndpoint, url.PathEscape(projectId), query.Encode())
+
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := f.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil, ErrModNotFound
+	} else if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("modrinth responded with status %d", res.StatusCode)
+	}
+
+	var versions []modrinthVersion
+	if err := json.NewDecoder(res.Body).Decode(&versions); err != nil {
+		return nil, err
+	}
+
+	// versions are listed newest first
+	for _, v := range versions {
+		for _, file := range v.Files {
+			if file.Primary || len(v.Files) == 1 {
+				return &Artifact{
+					ProjectID: v.ProjectID,
+					Name:      v.Name,
+					Version:   v.VersionNumber,
+					Kind:      modrinthKind(v.Loaders),
+					URL:       file.URL,
+				}, nil
+			}
+		}
+	}
+
+	return nil, ErrNoCompatibleFile
+}
+
+func modrinthKind(loaders []string) gamev1Model.ModKind {
+	for _, loader := range loaders {
+		if kind := KindForServerType(gamev1Model.ServerType(loader)); kind != "" {
+			return kind
+		}
+	}
+	return gamev1Model.ModContent
+}
@@ -0,0 +1,114 @@
+package mods
+
+import (
+	"errors"
+	"fmt"
+
+	gamev1Model "agones-minecraft/models/v1/game"
+	appHttp "agones-minecraft/services/http"
+)
+
+var (
+	ErrUnsupportedSource error = errors.New("unsupported mod source")
+	ErrModNotFound       error = errors.New("mod not found")
+	ErrNoCompatibleFile  error = errors.New("no mod version compatible with game server type and version")
+	ErrUnpinnedVersion   error = errors.New("mods can only be added to games on LATEST or a release version")
+)
+
+// Content not supported by a server type. e.g. plugins on Fabric or mods on vanilla servers
+type ErrIncompatibleServerType struct {
+	Kind gamev1Model.ModKind
+	Type gamev1Model.ServerType
+}
+
+func (e *ErrIncompatibleServerType) Error() string {
+	return fmt.Sprintf("%s servers do not support %ss", e.Type, e.Kind)
+}
+
+// Resolved mod or plugin download
+type Artifact struct {
+	ProjectID string
+	Name      string
+	Version   string
+	Kind      gamev1Model.ModKind
+	URL       string
+}
+
+// Resolves mod or plugin project IDs from a source into downloadable artifacts
+type Fetcher interface {
+	// Resolves the newest artifact for the project compatible with the server type and Minecraft version
+	Fetch(projectId string, serverType gamev1Model.ServerType, version string) (*Artifact, error)
+}
+
+var fetchers = map[gamev1Model.ModSource]Fetcher{}
+
+// Registers the default Modrinth and SpigotMC fetchers. Requires app http client to be initialized
+func Init() {
+	SetFetcher(gamev1Model.ModrinthSource, NewModrinthFetcher(appHttp.Client()))
+	SetFetcher(gamev1Model.SpigotSource, NewSpigetFetcher(appHttp.Client()))
+}
+
+// Sets the fetcher for a source. Used to replace remote fetchers with a local fake
+func SetFetcher(source gamev1Model.ModSource, fetcher Fetcher) {
+	fetchers[source] = fetcher
+}
+
+// Resolves a project from a source. Catalog projects are resolved through their upstream source
+func Resolve(source gamev1Model.ModSource, projectId string, serverType gamev1Model.ServerType, version string) (*Artifact, error) {
+	if KindForServerType(serverType) == "" {
+		return nil, &ErrIncompatibleServerType{gamev1Model.ModContent, serverType}
+	}
+
+	if source == gamev1Model.CatalogSource {
+		entry, ok := catalog[projectId]
+		if !ok {
+			return nil, ErrModNotFound
+		}
+		source = entry.Source
+		projectId = entry.ProjectID
+	}
+
+	fetcher, ok := fetchers[source]
+	if !ok {
+		return nil, ErrUnsupportedSource
+	}
+
+	artifact, err := fetcher.Fetch(projectId, serverType, version)
+	if err != nil {
+		return nil, err
+	}
+
+	if KindForServerType(serverType) != artifact.Kind {
+		return nil, &ErrIncompatibleServerType{artifact.Kind, serverType}
+	}
+
+	return artifact, nil
+}
+
+// Returns the kind of content a server type can load or an empty kind for servers without mod support
+func KindForServerType(serverType gamev1Model.ServerType) gamev1Model.ModKind {
+	switch serverType {
+	case gamev1Model.Fabric, gamev1Model.Forge:
+		return gamev1Model.ModContent
+	case gamev1Model.Paper, gamev1Model.Spigot:
+		return gamev1Model.PluginContent
+	}
+	return ""
+}
+
+// Curated project available by slug
+type CatalogEntry struct {
+	Slug      string
+	Name      string
+	Source    gamev1Model.ModSource
+	ProjectID string
+	Kind      gamev1Model.ModKind
+}
+
+var catalog = map[string]CatalogEntry{
+	"fabric-api":  {Slug: "fabric-api", Name: "Fabric API", Source: gamev1Model.ModrinthSource, ProjectID: "P7dR8mSH", Kind: gamev1Model.ModContent},
+	"lithium":     {Slug: "lithium", Name: "Lithium", Source: gamev1Model.ModrinthSource, ProjectID: "gvQqBUqZ", Kind: gamev1Model.ModContent},
+	"luckperms":   {Slug: "luckperms", Name: "LuckPerms", Source: gamev1Model.ModrinthSource, ProjectID: "Vebnzrzj", Kind: gamev1Model.PluginContent},
+	"worldedit":   {Slug: "worldedit", Name: "WorldEdit", Source: gamev1Model.ModrinthSource, ProjectID: "1u6JkXh5", Kind: gamev1Model.PluginContent},
+	"essentialsx": {Slug: "essentialsx", Name: "EssentialsX", Source: gamev1Model.SpigotSource, ProjectID: "9089", Kind: gamev1Model.PluginContent},
+}
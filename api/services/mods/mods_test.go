@@ -0,0 +1,124 @@
+package mods
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+
+	gamev1Model "agones-minecraft/models/v1/game"
+)
+
+// Replaces the fetchers for a test with fakes and restores them when the test ends
+func setFakeFetchers(t *testing.T, fake Fetcher) {
+	prev := fetchers
+	fetchers = map[gamev1Model.ModSource]Fetcher{}
+	t.Cleanup(func() { fetchers = prev })
+
+	SetFetcher(gamev1Model.ModrinthSource, fake)
+	SetFetcher(gamev1Model.SpigotSource, fake)
+}
+
+func newFake() *FakeFetcher {
+	fake := NewFakeFetcher(map[string]Artifact{
+		"P7dR8mSH": {ProjectID: "P7dR8mSH", Name: "Fabric API", Version: "0.40.1", Kind: gamev1Model.ModContent, URL: "https://cdn.modrinth.com/fabric-api.jar"},
+		"9089":     {ProjectID: "9089", Name: "EssentialsX", Version: "2.19.0", Kind: gamev1Model.PluginContent, URL: "https://api.spiget.org/essentialsx.jar"},
+	})
+	fake.Versions = map[string][]string{
+		"P7dR8mSH": {"1.17.1", gamev1Model.LatestVersion},
+	}
+	return fake
+}
+
+func TestResolve(t *testing.T) {
+	setFakeFetchers(t, newFake())
+
+	tests := []struct {
+		name       string
+		source     gamev1Model.ModSource
+		projectId  string
+		serverType gamev1Model.ServerType
+		version    string
+		want       string
+		wantErr    error
+	}{
+		{"project", gamev1Model.ModrinthSource, "P7dR8mSH", gamev1Model.Fabric, "1.17.1", "Fabric API", nil},
+		{"catalog slug", gamev1Model.CatalogSource, "fabric-api", gamev1Model.Fabric, gamev1Model.LatestVersion, "Fabric API", nil},
+		{"project without versions", gamev1Model.SpigotSource, "9089", gamev1Model.Paper, "1.16.5", "EssentialsX", nil},
+		{"project not found", gamev1Model.ModrinthSource, "unknown", gamev1Model.Fabric, "1.17.1", "", ErrModNotFound},
+		{"catalog slug not found", gamev1Model.CatalogSource, "unknown", gamev1Model.Fabric, "1.17.1", "", ErrModNotFound},
+		{"unsupported version", gamev1Model.ModrinthSource, "P7dR8mSH", gamev1Model.Fabric, "1.16.5", "", ErrNoCompatibleFile},
+		{"unsupported source", gamev1Model.ModSource("curseforge"), "P7dR8mSH", gamev1Model.Fabric, "1.17.1", "", ErrUnsupportedSource},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			artifact, err := Resolve(tt.source, tt.projectId, tt.serverType, tt.version)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Resolve() error = %v, want %v", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Resolve() error = %v", err)
+			}
+			if artifact.Name != tt.want {
+				t.Errorf("Resolve() name = %s, want %s", artifact.Name, tt.want)
+			}
+		})
+	}
+}
+
+func TestResolveIncompatibleServerType(t *testing.T) {
+	setFakeFetchers(t, newFake())
+
+	tests := []struct {
+		name       string
+		projectId  string
+		serverType gamev1Model.ServerType
+	}{
+		{"vanilla", "P7dR8mSH", gamev1Model.Vanilla},
+		{"mod on plugin server", "P7dR8mSH", gamev1Model.Paper},
+		{"plugin on mod server", "9089", gamev1Model.Fabric},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Resolve(gamev1Model.ModrinthSource, tt.projectId, tt.serverType, gamev1Model.LatestVersion)
+
+			var incompatible *ErrIncompatibleServerType
+			if !errors.As(err, &incompatible) {
+				t.Fatalf("Resolve() error = %v, want ErrIncompatibleServerType", err)
+			}
+			if incompatible.Type != tt.serverType {
+				t.Errorf("Resolve() server type = %s, want %s", incompatible.Type, tt.serverType)
+			}
+		})
+	}
+}
+
+// Fails every request so fetchers rejecting a version before requesting are detected
+type failTransport struct {
+	requests int
+}
+
+func (f *failTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	f.requests++
+	return nil, errors.New("unexpected request")
+}
+
+func TestModrinthFetchUnpinnedVersion(t *testing.T) {
+	for _, version := range []string{"SNAPSHOT", "PREVIOUS"} {
+		t.Run(version, func(t *testing.T) {
+			transport := &failTransport{}
+			fetcher := NewModrinthFetcher(&http.Client{Transport: transport})
+
+			if _, err := fetcher.Fetch("P7dR8mSH", gamev1Model.Fabric, version); !errors.Is(err, ErrUnpinnedVersion) {
+				t.Fatalf("Fetch() error = %v, want %v", err, ErrUnpinnedVersion)
+			}
+			if transport.requests != 0 {
+				t.Errorf("Fetch() made %d requests, want 0", transport.requests)
+			}
+		})
+	}
+}
@@ -0,0 +1,84 @@
+package mods
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+
+	gamev1Model "agones-minecraft/models/v1/game"
+)
+
+const (
+	SpigetEndpoint = "https://api.spiget.org/v2"
+)
+
+// Resolves SpigotMC resource IDs through the Spiget API
+type SpigetFetcher struct {
+	client *http.Client
+}
+
+func NewSpigetFetcher(client *http.Client) *SpigetFetcher {
+	return &SpigetFetcher{client}
+}
+
+type spigetResource struct {
+	ID       int    `json:"id"`
+	Name     string `json:"name"`
+	External bool   `json:"external"`
+	Version  struct {
+		ID int `json:"id"`
+	} `json:"version"`
+}
+
+type spigetVersion struct {
+	Name string `json:"name"`
+}
+
+// SpigotMC resources are plugins. Spiget does not track game versions so the latest resource version is used
+func (f *SpigetFetcher) Fetch(projectId string, serverType gamev1Model.ServerType, version string) (*Artifact, error) {
+	var resource spigetResource
+	if err := f.get(fmt.Sprintf("%s/resources/%s", SpigetEndpoint, url.PathEscape(projectId)), &resource); err != nil {
+		return nil, err
+	}
+
+	// externally hosted resources cannot be downloaded through spiget
+	if resource.External {
+		return nil, ErrNoCompatibleFile
+	}
+
+	var resourceVersion spigetVersion
+	if err := f.get(fmt.Sprintf("%s/resources/%s/versions/latest", SpigetEndpoint, url.PathEscape(projectId)), &resourceVersion); err != nil {
+		return nil, err
+	}
+
+	return &Artifact{
+		ProjectID: projectId,
+		Name:      resource.Name,
+		Version:   resourceVersion.Name,
+		Kind:      gamev1Model.PluginContent,
+		URL:       fmt.Sprintf("%s/resources/%s/download", SpigetEndpoint, url.PathEscape(projectId)),
+	}, nil
+}
+
+func (f *SpigetFetcher) get(endpoint string, v interface{}) error {
+	req, err := http.NewRequest("GET", endpoint, nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := f.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return ErrModNotFound
+	} else if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("spiget responded with status %d", res.StatusCode)
+	}
+
+	return json.NewDecoder(res.Body).Decode(v)
+}
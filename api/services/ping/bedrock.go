@@ -0,0 +1,105 @@
+package ping
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/rand"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	raknetUnconnectedPing byte = 0x01
+	raknetUnconnectedPong byte = 0x1c
+
+	// packet id, time, server guid, magic and string length
+	raknetPongHeaderSize int = 1 + 8 + 8 + 16 + 2
+	// Max RakNet datagram size
+	raknetMaxPacketSize int = 1500
+)
+
+// Identifies offline RakNet messages
+var raknetMagic []byte = []byte{0x00, 0xff, 0xff, 0x00, 0xfe, 0xfe, 0xfe, 0xfe, 0xfd, 0xfd, 0xfd, 0xfd, 0x12, 0x34, 0x56, 0x78}
+
+// Pings a Bedrock server with a RakNet unconnected ping.
+// https://wiki.vg/Raknet_Protocol#Unconnected_Ping
+func PingBedrock(address string, timeout time.Duration) (*Status, error) {
+	conn, err := net.DialTimeout("udp", address, timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return nil, err
+	}
+
+	start := time.Now()
+
+	var ping bytes.Buffer
+	ping.WriteByte(raknetUnconnectedPing)
+	binary.Write(&ping, binary.BigEndian, start.UnixNano()/int64(time.Millisecond))
+	ping.Write(raknetMagic)
+	binary.Write(&ping, binary.BigEndian, rand.Int63())
+
+	if _, err := conn.Write(ping.Bytes()); err != nil {
+		return nil, err
+	}
+
+	buf := make([]byte, raknetMaxPacketSize)
+	n, err := conn.Read(buf)
+	if err != nil {
+		return nil, err
+	}
+
+	latency := time.Since(start)
+
+	serverId, err := parseBedrockPong(buf[:n])
+	if err != nil {
+		return nil, err
+	}
+
+	// MCPE;<motd>;<protocol>;<version>;<online>;<max>;<server guid>;<world>;<gamemode>;...
+	fields := strings.Split(serverId, ";")
+	if len(fields) < 6 {
+		return nil, ErrInvalidResponse
+	}
+
+	online, err := strconv.Atoi(fields[4])
+	if err != nil {
+		return nil, ErrInvalidResponse
+	}
+
+	max, err := strconv.Atoi(fields[5])
+	if err != nil {
+		return nil, ErrInvalidResponse
+	}
+
+	return &Status{
+		Version:       fields[3],
+		MOTD:          stripFormatting(fields[1]),
+		PlayersOnline: online,
+		PlayersMax:    max,
+		Latency:       latency,
+	}, nil
+}
+
+// Returns the server id string of an unconnected pong
+func parseBedrockPong(packet []byte) (string, error) {
+	if len(packet) < raknetPongHeaderSize || packet[0] != raknetUnconnectedPong {
+		return "", ErrInvalidResponse
+	}
+
+	if !bytes.Equal(packet[17:33], raknetMagic) {
+		return "", ErrInvalidResponse
+	}
+
+	length := int(binary.BigEndian.Uint16(packet[33:35]))
+	if len(packet) < raknetPongHeaderSize+length {
+		return "", ErrInvalidResponse
+	}
+
+	return string(packet[raknetPongHeaderSize : raknetPongHeaderSize+length]), nil
+}
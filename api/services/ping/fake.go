@@ -0,0 +1,142 @@
+package ping
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net"
+)
+
+// Local server answering pings with a fixed status
+type FakeServer struct {
+	status   *Status
+	listener net.Listener
+	conn     net.PacketConn
+}
+
+// Starts a fake Java server on a random local port answering Server List Pings
+func NewFakeJavaServer(status *Status) (*FakeServer, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &FakeServer{status: status, listener: listener}
+	go s.serveJava()
+
+	return s, nil
+}
+
+// Starts a fake Bedrock server on a random local port answering unconnected pings
+func NewFakeBedrockServer(status *Status) (*FakeServer, error) {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		return nil, err
+	}
+
+	s := &FakeServer{status: status, conn: conn}
+	go s.serveBedrock()
+
+	return s, nil
+}
+
+// Address to ping
+func (s *FakeServer) Address() string {
+	if s.listener != nil {
+		return s.listener.Addr().String()
+	}
+	return s.conn.LocalAddr().String()
+}
+
+func (s *FakeServer) Close() error {
+	if s.listener != nil {
+		return s.listener.Close()
+	}
+	return s.conn.Close()
+}
+
+func (s *FakeServer) serveJava() {
+	for {
+		conn, err := s.listener.Accept()
+		if err != nil {
+			return
+		}
+		go s.handleJava(conn)
+	}
+}
+
+func (s *FakeServer) handleJava(conn net.Conn) {
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+
+	// handshake and status request
+	for i := 0; i < 2; i++ {
+		if _, err := readPacket(r); err != nil {
+			return
+		}
+	}
+
+	var res javaStatusResponse
+	res.Version.Name = s.status.Version
+	res.Players.Online = s.status.PlayersOnline
+	res.Players.Max = s.status.PlayersMax
+	for _, name := range s.status.Players {
+		res.Players.Sample = append(res.Players.Sample, struct {
+			Name string `json:"name"`
+			ID   string `json:"id"`
+		}{Name: name})
+	}
+	res.Description, _ = json.Marshal(chatComponent{Text: s.status.MOTD})
+
+	body, err := json.Marshal(res)
+	if err != nil {
+		return
+	}
+
+	var status bytes.Buffer
+	writeVarInt(&status, javaStatusPacketId)
+	writeString(&status, string(body))
+
+	if err := writePacket(conn, status.Bytes()); err != nil {
+		return
+	}
+
+	// pongs echo the ping payload
+	ping, err := readPacket(r)
+	if err != nil {
+		return
+	}
+
+	writePacket(conn, ping)
+}
+
+func (s *FakeServer) serveBedrock() {
+	buf := make([]byte, raknetMaxPacketSize)
+	for {
+		n, addr, err := s.conn.ReadFrom(buf)
+		if err != nil {
+			return
+		}
+
+		// packet id, time, magic and client guid
+		if n < 33 || buf[0] != raknetUnconnectedPing {
+			continue
+		}
+
+		serverId := fmt.Sprintf("MCPE;%s;448;%s;%d;%d;1;Bedrock level;Survival;1;",
+			s.status.MOTD, s.status.Version, s.status.PlayersOnline, s.status.PlayersMax)
+
+		var pong bytes.Buffer
+		pong.WriteByte(raknetUnconnectedPong)
+		pong.Write(buf[1:9])
+		binary.Write(&pong, binary.BigEndian, int64(1))
+		pong.Write(raknetMagic)
+		binary.Write(&pong, binary.BigEndian, uint16(len(serverId)))
+		pong.WriteString(serverId)
+
+		s.conn.WriteTo(pong.Bytes(), addr)
+	}
+}
@@ -0,0 +1,278 @@
+package ping
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// Servers respond with their own protocol version when pinged with -1
+	javaProtocolVersion int32 = -1
+	javaStatusState     int32 = 1
+
+	javaStatusPacketId int32 = 0x00
+	javaPingPacketId   int32 = 0x01
+
+	// Status responses include the server icon so they can be fairly large
+	maxJavaPacketLength int32 = 1 << 20
+)
+
+var (
+	ErrVarIntTooLong  error = errors.New("varint too long")
+	ErrPacketTooLong  error = errors.New("packet too long")
+	ErrStringTooLong  error = errors.New("string too long")
+	ErrNegativeLength error = errors.New("negative length")
+)
+
+type javaStatusResponse struct {
+	Version struct {
+		Name     string `json:"name"`
+		Protocol int    `json:"protocol"`
+	} `json:"version"`
+	Players struct {
+		Max    int `json:"max"`
+		Online int `json:"online"`
+		Sample []struct {
+			Name string `json:"name"`
+			ID   string `json:"id"`
+		} `json:"sample"`
+	} `json:"players"`
+	Description json.RawMessage `json:"description"`
+}
+
+// Chat component used in server descriptions. e.g. {"text":"A ","extra":[{"text":"Minecraft Server"}]}
+type chatComponent struct {
+	Text  string          `json:"text"`
+	Extra []chatComponent `json:"extra"`
+}
+
+func (c chatComponent) String() string {
+	var sb strings.Builder
+	sb.WriteString(c.Text)
+	for _, extra := range c.Extra {
+		sb.WriteString(extra.String())
+	}
+	return sb.String()
+}
+
+// Pings a Java server with the Server List Ping protocol.
+// https://wiki.vg/Server_List_Ping
+func PingJava(address string, timeout time.Duration) (*Status, error) {
+	host, portStr, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+
+	port, err := strconv.ParseUint(portStr, 10, 16)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	if err := conn.SetDeadline(time.Now().Add(timeout)); err != nil {
+		return nil, err
+	}
+
+	var handshake bytes.Buffer
+	writeVarInt(&handshake, 0x00)
+	writeVarInt(&handshake, javaProtocolVersion)
+	writeString(&handshake, host)
+	binary.Write(&handshake, binary.BigEndian, uint16(port))
+	writeVarInt(&handshake, javaStatusState)
+
+	var request bytes.Buffer
+	writeVarInt(&request, javaStatusPacketId)
+
+	start := time.Now()
+
+	if err := writePacket(conn, handshake.Bytes()); err != nil {
+		return nil, err
+	}
+	if err := writePacket(conn, request.Bytes()); err != nil {
+		return nil, err
+	}
+
+	r := bufio.NewReader(conn)
+
+	payload, err := readPacket(r)
+	if err != nil {
+		return nil, err
+	}
+
+	latency := time.Since(start)
+
+	pr := bytes.NewReader(payload)
+	if id, err := readVarInt(pr); err != nil || id != javaStatusPacketId {
+		return nil, ErrInvalidResponse
+	}
+
+	body, err := readString(pr)
+	if err != nil {
+		return nil, err
+	}
+
+	var res javaStatusResponse
+	if err := json.Unmarshal([]byte(body), &res); err != nil {
+		return nil, ErrInvalidResponse
+	}
+
+	// latency is taken from the status round trip for servers that do not answer pings
+	if pingLatency, err := pingJava(conn, r); err == nil {
+		latency = pingLatency
+	}
+
+	status := &Status{
+		Version:       res.Version.Name,
+		MOTD:          parseDescription(res.Description),
+		PlayersOnline: res.Players.Online,
+		PlayersMax:    res.Players.Max,
+		Latency:       latency,
+	}
+
+	for _, player := range res.Players.Sample {
+		status.Players = append(status.Players, player.Name)
+	}
+
+	return status, nil
+}
+
+// Sends a ping packet and waits for its pong
+func pingJava(conn net.Conn, r *bufio.Reader) (time.Duration, error) {
+	start := time.Now()
+
+	var ping bytes.Buffer
+	writeVarInt(&ping, javaPingPacketId)
+	binary.Write(&ping, binary.BigEndian, start.UnixNano())
+
+	if err := writePacket(conn, ping.Bytes()); err != nil {
+		return 0, err
+	}
+
+	payload, err := readPacket(r)
+	if err != nil {
+		return 0, err
+	}
+
+	pr := bytes.NewReader(payload)
+	if id, err := readVarInt(pr); err != nil || id != javaPingPacketId {
+		return 0, ErrInvalidResponse
+	}
+
+	var payloadTime int64
+	if err := binary.Read(pr, binary.BigEndian, &payloadTime); err != nil || payloadTime != start.UnixNano() {
+		return 0, ErrInvalidResponse
+	}
+
+	return time.Since(start), nil
+}
+
+// Descriptions are either plain strings or chat components
+func parseDescription(raw json.RawMessage) string {
+	var text string
+	if err := json.Unmarshal(raw, &text); err == nil {
+		return stripFormatting(text)
+	}
+
+	var component chatComponent
+	if err := json.Unmarshal(raw, &component); err == nil {
+		return stripFormatting(component.String())
+	}
+
+	return ""
+}
+
+func writePacket(w io.Writer, payload []byte) error {
+	var packet bytes.Buffer
+	writeVarInt(&packet, int32(len(payload)))
+	packet.Write(payload)
+
+	_, err := w.Write(packet.Bytes())
+	return err
+}
+
+func readPacket(r *bufio.Reader) ([]byte, error) {
+	length, err := readVarInt(r)
+	if err != nil {
+		return nil, err
+	}
+
+	if length < 0 {
+		return nil, ErrNegativeLength
+	} else if length > maxJavaPacketLength {
+		return nil, ErrPacketTooLong
+	}
+
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return nil, err
+	}
+
+	return payload, nil
+}
+
+func writeVarInt(buf *bytes.Buffer, value int32) {
+	v := uint32(value)
+	for {
+		if v&^0x7f == 0 {
+			buf.WriteByte(byte(v))
+			return
+		}
+		buf.WriteByte(byte(v&0x7f | 0x80))
+		v >>= 7
+	}
+}
+
+func readVarInt(r io.ByteReader) (int32, error) {
+	var value uint32
+	for i := 0; i < 5; i++ {
+		b, err := r.ReadByte()
+		if err != nil {
+			return 0, err
+		}
+
+		value |= uint32(b&0x7f) << (7 * i)
+
+		if b&0x80 == 0 {
+			return int32(value), nil
+		}
+	}
+	return 0, ErrVarIntTooLong
+}
+
+func writeString(buf *bytes.Buffer, s string) {
+	writeVarInt(buf, int32(len(s)))
+	buf.WriteString(s)
+}
+
+func readString(r *bytes.Reader) (string, error) {
+	length, err := readVarInt(r)
+	if err != nil {
+		return "", err
+	}
+
+	if length < 0 {
+		return "", ErrNegativeLength
+	} else if int(length) > r.Len() {
+		return "", ErrStringTooLong
+	}
+
+	b := make([]byte, length)
+	if _, err := io.ReadFull(r, b); err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
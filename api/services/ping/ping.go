@@ -0,0 +1,100 @@
+package ping
+
+import (
+	"errors"
+	"regexp"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultTimeout time.Duration = time.Second * 2
+	// Statuses are cached briefly so that listing games does not ping every server on every request
+	DefaultCacheTTL time.Duration = time.Second * 10
+)
+
+var (
+	ErrInvalidResponse error = errors.New("invalid ping response")
+
+	// Legacy color and formatting codes. e.g. §a
+	formattingCodes *regexp.Regexp = regexp.MustCompile("§.")
+
+	defaultCache *Cache = NewCache(DefaultCacheTTL)
+)
+
+// Status reported by a running Minecraft server
+type Status struct {
+	Version       string
+	MOTD          string
+	PlayersOnline int
+	PlayersMax    int
+	// Names of online players. Java servers only report a sample of up to 12 players
+	// and Bedrock servers do not report player names
+	Players []string
+	Latency time.Duration
+}
+
+// Pings a Java server with the default timeout. Results are cached
+func Java(address string) (*Status, error) {
+	return defaultCache.get("java/"+address, func() (*Status, error) {
+		return PingJava(address, DefaultTimeout)
+	})
+}
+
+// Pings a Bedrock server with the default timeout. Results are cached
+func Bedrock(address string) (*Status, error) {
+	return defaultCache.get("bedrock/"+address, func() (*Status, error) {
+		return PingBedrock(address, DefaultTimeout)
+	})
+}
+
+type cacheEntry struct {
+	status  *Status
+	err     error
+	expires time.Time
+}
+
+// Short lived cache of ping results by address. Failed pings are cached too
+// so that unresponsive servers are not retried on every request
+type Cache struct {
+	mu      sync.Mutex
+	ttl     time.Duration
+	entries map[string]*cacheEntry
+}
+
+func NewCache(ttl time.Duration) *Cache {
+	return &Cache{
+		ttl:     ttl,
+		entries: map[string]*cacheEntry{},
+	}
+}
+
+func (c *Cache) get(key string, ping func() (*Status, error)) (*Status, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[key]
+	c.mu.Unlock()
+
+	if ok && time.Now().Before(entry.expires) {
+		return entry.status, entry.err
+	}
+
+	status, err := ping()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries[key] = &cacheEntry{status: status, err: err, expires: time.Now().Add(c.ttl)}
+
+	// drop expired entries so servers that are gone do not accumulate
+	for k, e := range c.entries {
+		if time.Now().After(e.expires) {
+			delete(c.entries, k)
+		}
+	}
+
+	return status, err
+}
+
+func stripFormatting(s string) string {
+	return formattingCodes.ReplaceAllString(s, "")
+}
@@ -0,0 +1,225 @@
+package ping
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"net"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+const testTimeout time.Duration = time.Millisecond * 500
+
+func TestPingJava(t *testing.T) {
+	want := &Status{
+		Version:       "1.17.1",
+		MOTD:          "A Minecraft Server",
+		PlayersOnline: 2,
+		PlayersMax:    10,
+		Players:       []string{"Notch", "jeb_"},
+	}
+
+	server, err := NewFakeJavaServer(&Status{
+		Version:       want.Version,
+		MOTD:          "§aA Minecraft Server",
+		PlayersOnline: want.PlayersOnline,
+		PlayersMax:    want.PlayersMax,
+		Players:       want.Players,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	status, err := PingJava(server.Address(), testTimeout)
+	if err != nil {
+		t.Fatalf("PingJava() error = %v", err)
+	}
+
+	status.Latency = 0
+	if !reflect.DeepEqual(status, want) {
+		t.Errorf("PingJava() = %+v, want %+v", status, want)
+	}
+}
+
+func TestPingBedrock(t *testing.T) {
+	want := &Status{
+		Version:       "1.17.11",
+		MOTD:          "Dedicated Server",
+		PlayersOnline: 1,
+		PlayersMax:    10,
+	}
+
+	server, err := NewFakeBedrockServer(&Status{
+		Version:       want.Version,
+		MOTD:          "§bDedicated Server",
+		PlayersOnline: want.PlayersOnline,
+		PlayersMax:    want.PlayersMax,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer server.Close()
+
+	status, err := PingBedrock(server.Address(), testTimeout)
+	if err != nil {
+		t.Fatalf("PingBedrock() error = %v", err)
+	}
+
+	status.Latency = 0
+	if !reflect.DeepEqual(status, want) {
+		t.Errorf("PingBedrock() = %+v, want %+v", status, want)
+	}
+}
+
+// Starts a TCP server that answers every connection with the response and returns its address
+func serveJavaResponse(t *testing.T, response []byte) string {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// connections are left open until the test ends so that responses are not cut short by an EOF
+	var mu sync.Mutex
+	var conns []net.Conn
+
+	t.Cleanup(func() {
+		listener.Close()
+		mu.Lock()
+		defer mu.Unlock()
+		for _, conn := range conns {
+			conn.Close()
+		}
+	})
+
+	go func() {
+		for {
+			conn, err := listener.Accept()
+			if err != nil {
+				return
+			}
+			mu.Lock()
+			conns = append(conns, conn)
+			mu.Unlock()
+			conn.Write(response)
+		}
+	}()
+
+	return listener.Addr().String()
+}
+
+func javaPacket(payload func(buf *bytes.Buffer)) []byte {
+	var body bytes.Buffer
+	payload(&body)
+
+	var packet bytes.Buffer
+	writePacket(&packet, body.Bytes())
+	return packet.Bytes()
+}
+
+func TestPingJavaMalformed(t *testing.T) {
+	tests := []struct {
+		name     string
+		response []byte
+		wantErr  error
+	}{
+		{"wrong packet id", javaPacket(func(buf *bytes.Buffer) {
+			writeVarInt(buf, javaPingPacketId)
+			writeString(buf, "{}")
+		}), ErrInvalidResponse},
+		{"invalid json", javaPacket(func(buf *bytes.Buffer) {
+			writeVarInt(buf, javaStatusPacketId)
+			writeString(buf, "{")
+		}), ErrInvalidResponse},
+		{"string longer than packet", javaPacket(func(buf *bytes.Buffer) {
+			writeVarInt(buf, javaStatusPacketId)
+			writeVarInt(buf, 100)
+			buf.WriteString("{}")
+		}), ErrStringTooLong},
+		{"negative length", []byte{0xff, 0xff, 0xff, 0xff, 0x0f}, ErrNegativeLength},
+		{"packet too long", []byte{0xff, 0xff, 0xff, 0x7f}, ErrPacketTooLong},
+		{"varint too long", []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0x01}, ErrVarIntTooLong},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			address := serveJavaResponse(t, tt.response)
+
+			if _, err := PingJava(address, testTimeout); !errors.Is(err, tt.wantErr) {
+				t.Errorf("PingJava() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseBedrockPongMalformed(t *testing.T) {
+	pong := func(id byte, magic []byte, length uint16, serverId string) []byte {
+		var buf bytes.Buffer
+		buf.WriteByte(id)
+		binary.Write(&buf, binary.BigEndian, int64(0))
+		binary.Write(&buf, binary.BigEndian, int64(1))
+		buf.Write(magic)
+		binary.Write(&buf, binary.BigEndian, length)
+		buf.WriteString(serverId)
+		return buf.Bytes()
+	}
+
+	serverId := "MCPE;Dedicated Server;448;1.17.11;1;10;1;Bedrock level;Survival;1;"
+
+	tests := []struct {
+		name   string
+		packet []byte
+	}{
+		{"empty", nil},
+		{"short header", []byte{raknetUnconnectedPong, 0x00}},
+		{"wrong packet id", pong(raknetUnconnectedPing, raknetMagic, uint16(len(serverId)), serverId)},
+		{"wrong magic", pong(raknetUnconnectedPong, make([]byte, len(raknetMagic)), uint16(len(serverId)), serverId)},
+		{"length past packet", pong(raknetUnconnectedPong, raknetMagic, uint16(len(serverId)+1), serverId)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parseBedrockPong(tt.packet); err != ErrInvalidResponse {
+				t.Errorf("parseBedrockPong() error = %v, want %v", err, ErrInvalidResponse)
+			}
+		})
+	}
+
+	if got, err := parseBedrockPong(pong(raknetUnconnectedPong, raknetMagic, uint16(len(serverId)), serverId)); err != nil || got != serverId {
+		t.Errorf("parseBedrockPong() = %q, %v, want %q", got, err, serverId)
+	}
+}
+
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+func TestPingJavaTimeout(t *testing.T) {
+	// accepts connections without ever responding
+	address := serveJavaResponse(t, nil)
+
+	start := time.Now()
+	if _, err := PingJava(address, testTimeout); !isTimeout(err) {
+		t.Fatalf("PingJava() error = %v, want timeout", err)
+	}
+	if elapsed := time.Since(start); elapsed > testTimeout*4 {
+		t.Errorf("PingJava() took %s with a %s timeout", elapsed, testTimeout)
+	}
+}
+
+func TestPingBedrockTimeout(t *testing.T) {
+	// receives pings without ever responding
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	if _, err := PingBedrock(conn.LocalAddr().String(), testTimeout); !isTimeout(err) {
+		t.Fatalf("PingBedrock() error = %v, want timeout", err)
+	}
+}
@@ -0,0 +1,147 @@
+package rcon
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"time"
+)
+
+const (
+	// RCON packet types
+	responseType int32 = 0
+	commandType  int32 = 2
+	authType     int32 = 3
+
+	// Server rejects packets with bodies over 1446 bytes
+	MaxCommandLength int = 1446
+
+	DefaultTimeout time.Duration = time.Second * 5
+
+	// id, type and two null terminators
+	headerSize int32 = 10
+	// Max response packet size sent by the server
+	maxPacketSize int32 = 4096 + headerSize
+)
+
+var (
+	ErrAuthFailed      error = errors.New("rcon authentication failed")
+	ErrCommandTooLong  error = errors.New("rcon command too long")
+	ErrInvalidResponse error = errors.New("invalid rcon response")
+)
+
+// Minecraft RCON client
+type Client struct {
+	conn      net.Conn
+	requestId int32
+	timeout   time.Duration
+}
+
+// Connects and authenticates to a Minecraft server's RCON port
+func Dial(address string, password string, timeout time.Duration) (*Client, error) {
+	conn, err := net.DialTimeout("tcp", address, timeout)
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Client{conn: conn, timeout: timeout}
+
+	if err := c.auth(password); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return c, nil
+}
+
+// Executes a command and returns the server's response
+func (c *Client) Command(command string) (string, error) {
+	if len(command) > MaxCommandLength {
+		return "", ErrCommandTooLong
+	}
+
+	id, err := c.write(commandType, command)
+	if err != nil {
+		return "", err
+	}
+
+	resId, resType, body, err := c.read()
+	if err != nil {
+		return "", err
+	}
+
+	if resId != id || resType != responseType {
+		return "", ErrInvalidResponse
+	}
+
+	return body, nil
+}
+
+func (c *Client) Close() error {
+	return c.conn.Close()
+}
+
+func (c *Client) auth(password string) error {
+	id, err := c.write(authType, password)
+	if err != nil {
+		return err
+	}
+
+	resId, _, _, err := c.read()
+	if err != nil {
+		return err
+	}
+
+	// failed auth responds with a request id of -1
+	if resId == -1 || resId != id {
+		return ErrAuthFailed
+	}
+
+	return nil
+}
+
+func (c *Client) write(packetType int32, body string) (int32, error) {
+	c.requestId++
+	id := c.requestId
+
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, int32(len(body))+headerSize)
+	binary.Write(&buf, binary.LittleEndian, id)
+	binary.Write(&buf, binary.LittleEndian, packetType)
+	buf.WriteString(body)
+	buf.Write([]byte{0, 0})
+
+	c.conn.SetWriteDeadline(time.Now().Add(c.timeout))
+	if _, err := c.conn.Write(buf.Bytes()); err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (c *Client) read() (int32, int32, string, error) {
+	c.conn.SetReadDeadline(time.Now().Add(c.timeout))
+
+	var size int32
+	if err := binary.Read(c.conn, binary.LittleEndian, &size); err != nil {
+		return 0, 0, "", err
+	}
+
+	if size < headerSize || size > maxPacketSize {
+		return 0, 0, "", fmt.Errorf("%w: packet size %d", ErrInvalidResponse, size)
+	}
+
+	packet := make([]byte, size)
+	if _, err := io.ReadFull(c.conn, packet); err != nil {
+		return 0, 0, "", err
+	}
+
+	id := int32(binary.LittleEndian.Uint32(packet[0:4]))
+	packetType := int32(binary.LittleEndian.Uint32(packet[4:8]))
+	body := string(bytes.TrimRight(packet[8:], "\x00"))
+
+	return id, packetType, body, nil
+}
@@ -0,0 +1,432 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/jwt"
+)
+
+const (
+	GCSEndpoint       string = "https://storage.googleapis.com"
+	GCSScope          string = "https://www.googleapis.com/auth/devstorage.read_write"
+	IAMSignEndpoint   string = "https://iamcredentials.googleapis.com/v1/projects/-/serviceAccounts/%s:signBlob"
+	GoogleTokenURL    string = "https://oauth2.googleapis.com/token"
+	MetadataEndpoint  string = "http://metadata.google.internal/computeMetadata/v1/instance/service-accounts/default"
+	credentialsEnvVar string = "GOOGLE_APPLICATION_CREDENTIALS"
+
+	// V4 signed URLs expire after at most 7 days
+	MaxSignedURLExpiry time.Duration = time.Hour * 24 * 7
+
+	signingAlgorithm string = "GOOG4-RSA-SHA256"
+)
+
+var (
+	ErrInvalidCredentials error = errors.New("invalid service account credentials")
+
+	metadataClient *http.Client = &http.Client{Timeout: time.Second * 5}
+	// Replaced in tests
+	metadataURL string = MetadataEndpoint
+)
+
+type serviceAccountKey struct {
+	Type         string `json:"type"`
+	ClientEmail  string `json:"client_email"`
+	PrivateKey   string `json:"private_key"`
+	PrivateKeyID string `json:"private_key_id"`
+	TokenURI     string `json:"token_uri"`
+}
+
+// Google Cloud Storage through its JSON API. Authenticates with a service account key file
+// or with the GKE metadata server's service account when no key file is configured
+type GCSStorage struct {
+	bucket string
+	client *http.Client
+	email  string
+	// JSON API and signed URL endpoint
+	endpoint string
+	// signs blobs with the service account's key
+	sign func(payload []byte) ([]byte, error)
+}
+
+type gcsObject struct {
+	Name    string    `json:"name"`
+	Size    string    `json:"size"`
+	Updated time.Time `json:"updated"`
+}
+
+type gcsObjectList struct {
+	Items         []gcsObject `json:"items"`
+	NextPageToken string      `json:"nextPageToken"`
+}
+
+func NewGCSStorage(bucket string, credentialsFile string) (*GCSStorage, error) {
+	if credentialsFile == "" {
+		credentialsFile = os.Getenv(credentialsEnvVar)
+	}
+
+	if credentialsFile == "" {
+		return newMetadataGCSStorage(bucket)
+	}
+
+	b, err := ioutil.ReadFile(credentialsFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var key serviceAccountKey
+	if err := json.Unmarshal(b, &key); err != nil || key.Type != "service_account" {
+		return nil, ErrInvalidCredentials
+	}
+
+	privateKey, err := parsePrivateKey(key.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	tokenURL := key.TokenURI
+	if tokenURL == "" {
+		tokenURL = GoogleTokenURL
+	}
+
+	jwtConfig := &jwt.Config{
+		Email:        key.ClientEmail,
+		PrivateKey:   []byte(key.PrivateKey),
+		PrivateKeyID: key.PrivateKeyID,
+		Scopes:       []string{GCSScope},
+		TokenURL:     tokenURL,
+	}
+
+	return &GCSStorage{
+		bucket:   bucket,
+		client:   jwtConfig.Client(context.Background()),
+		email:    key.ClientEmail,
+		endpoint: GCSEndpoint,
+		sign: func(payload []byte) ([]byte, error) {
+			hashed := sha256.Sum256(payload)
+			return rsa.SignPKCS1v15(rand.Reader, privateKey, crypto.SHA256, hashed[:])
+		},
+	}, nil
+}
+
+// Uses the metadata server's default service account. URLs are signed through the IAM Credentials API
+// which requires the service account to have the Service Account Token Creator role on itself
+func newMetadataGCSStorage(bucket string) (*GCSStorage, error) {
+	email, err := getMetadata("/email")
+	if err != nil {
+		return nil, err
+	}
+
+	client := oauth2.NewClient(context.Background(), oauth2.ReuseTokenSource(nil, metadataTokenSource{}))
+
+	s := &GCSStorage{
+		bucket:   bucket,
+		client:   client,
+		email:    email,
+		endpoint: GCSEndpoint,
+	}
+
+	s.sign = func(payload []byte) ([]byte, error) {
+		return s.signBlob(payload)
+	}
+
+	return s, nil
+}
+
+func (s *GCSStorage) List(prefix string) ([]*Object, error) {
+	objects := []*Object{}
+	pageToken := ""
+
+	for {
+		query := url.Values{}
+		query.Set("prefix", prefix)
+		query.Set("fields", "items(name,size,updated),nextPageToken")
+		if pageToken != "" {
+			query.Set("pageToken", pageToken)
+		}
+
+		res, err := s.client.Get(fmt.Sprintf("%s/storage/v1/b/%s/o?%s", s.endpoint, url.PathEscape(s.bucket), query.Encode()))
+		if err != nil {
+			return nil, err
+		}
+
+		var list gcsObjectList
+		err = decodeGCSResponse(res, &list)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, item := range list.Items {
+			var size int64
+			fmt.Sscan(item.Size, &size)
+			objects = append(objects, &Object{Name: item.Name, Size: size, Updated: item.Updated})
+		}
+
+		if list.NextPageToken == "" {
+			return objects, nil
+		}
+		pageToken = list.NextPageToken
+	}
+}
+
+// Returns a V4 signed URL.
+// https://cloud.google.com/storage/docs/access-control/signing-urls-manually
+func (s *GCSStorage) SignedURL(name string, expires time.Duration) (string, error) {
+	return s.signedURL(name, expires, time.Now())
+}
+
+func (s *GCSStorage) signedURL(name string, expires time.Duration, now time.Time) (string, error) {
+	if name == "" {
+		return "", ErrInvalidObjectName
+	}
+
+	if expires > MaxSignedURLExpiry {
+		expires = MaxSignedURLExpiry
+	}
+
+	u, err := url.Parse(s.endpoint)
+	if err != nil {
+		return "", err
+	}
+
+	req := newV4Request(u.Host, s.email, s.bucket, name, expires, now)
+
+	signature, err := s.sign([]byte(req.stringToSign))
+	if err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("%s%s?%s&X-Goog-Signature=%s", s.endpoint, req.path, req.canonicalQuery, hex.EncodeToString(signature)), nil
+}
+
+// Signed parts of a V4 signed GET URL
+type v4Request struct {
+	path             string
+	canonicalQuery   string
+	canonicalRequest string
+	stringToSign     string
+}
+
+func newV4Request(host string, email string, bucket string, name string, expires time.Duration, now time.Time) *v4Request {
+	now = now.UTC()
+	datestamp := now.Format("20060102")
+	timestamp := now.Format("20060102T150405Z")
+	scope := datestamp + "/auto/storage/goog4_request"
+
+	path := "/" + bucket + "/" + escapeObjectName(name)
+
+	query := url.Values{}
+	query.Set("X-Goog-Algorithm", signingAlgorithm)
+	query.Set("X-Goog-Credential", email+"/"+scope)
+	query.Set("X-Goog-Date", timestamp)
+	query.Set("X-Goog-Expires", fmt.Sprintf("%d", int64(expires.Seconds())))
+	query.Set("X-Goog-SignedHeaders", "host")
+
+	// url.Values are encoded sorted by key as required for the canonical query string
+	canonicalQuery := strings.ReplaceAll(query.Encode(), "+", "%20")
+
+	canonicalRequest := strings.Join([]string{
+		http.MethodGet,
+		path,
+		canonicalQuery,
+		"host:" + host,
+		"",
+		"host",
+		"UNSIGNED-PAYLOAD",
+	}, "\n")
+
+	hashedRequest := sha256.Sum256([]byte(canonicalRequest))
+
+	stringToSign := strings.Join([]string{
+		signingAlgorithm,
+		timestamp,
+		scope,
+		hex.EncodeToString(hashedRequest[:]),
+	}, "\n")
+
+	return &v4Request{
+		path:             path,
+		canonicalQuery:   canonicalQuery,
+		canonicalRequest: canonicalRequest,
+		stringToSign:     stringToSign,
+	}
+}
+
+func (s *GCSStorage) Upload(name string, r io.Reader) error {
+	if name == "" {
+		return ErrInvalidObjectName
+	}
+
+	query := url.Values{}
+	query.Set("uploadType", "media")
+	query.Set("name", name)
+
+	res, err := s.client.Post(fmt.Sprintf("%s/upload/storage/v1/b/%s/o?%s", s.endpoint, url.PathEscape(s.bucket), query.Encode()), "application/octet-stream", r)
+	if err != nil {
+		return err
+	}
+
+	return decodeGCSResponse(res, nil)
+}
+
+func (s *GCSStorage) Delete(name string) error {
+	if name == "" {
+		return ErrInvalidObjectName
+	}
+
+	req, err := http.NewRequest(http.MethodDelete, fmt.Sprintf("%s/storage/v1/b/%s/o/%s", s.endpoint, url.PathEscape(s.bucket), url.PathEscape(name)), nil)
+	if err != nil {
+		return err
+	}
+
+	res, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+
+	return decodeGCSResponse(res, nil)
+}
+
+func (s *GCSStorage) signBlob(payload []byte) ([]byte, error) {
+	body, err := json.Marshal(map[string]string{"payload": base64.StdEncoding.EncodeToString(payload)})
+	if err != nil {
+		return nil, err
+	}
+
+	res, err := s.client.Post(fmt.Sprintf(IAMSignEndpoint, url.PathEscape(s.email)), "application/json", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+
+	var signed struct {
+		SignedBlob string `json:"signedBlob"`
+	}
+	if err := decodeGCSResponse(res, &signed); err != nil {
+		return nil, err
+	}
+
+	return base64.StdEncoding.DecodeString(signed.SignedBlob)
+}
+
+func decodeGCSResponse(res *http.Response, v interface{}) error {
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return ErrObjectNotFound
+	}
+
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		b, _ := ioutil.ReadAll(res.Body)
+		return fmt.Errorf("storage request failed with status %d: %s", res.StatusCode, strings.TrimSpace(string(b)))
+	}
+
+	if v == nil {
+		return nil
+	}
+
+	return json.NewDecoder(res.Body).Decode(v)
+}
+
+// Percent-encodes object names for paths. Only unreserved characters and slashes are kept as V4 signing requires
+func escapeObjectName(name string) string {
+	var sb strings.Builder
+	for i := 0; i < len(name); i++ {
+		c := name[i]
+		if 'A' <= c && c <= 'Z' || 'a' <= c && c <= 'z' || '0' <= c && c <= '9' || strings.IndexByte("-._~/", c) >= 0 {
+			sb.WriteByte(c)
+		} else {
+			fmt.Fprintf(&sb, "%%%02X", c)
+		}
+	}
+	return sb.String()
+}
+
+func parsePrivateKey(key string) (*rsa.PrivateKey, error) {
+	block, _ := pem.Decode([]byte(key))
+	if block == nil {
+		return nil, ErrInvalidCredentials
+	}
+
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		if parsed, err = x509.ParsePKCS1PrivateKey(block.Bytes); err != nil {
+			return nil, ErrInvalidCredentials
+		}
+	}
+
+	privateKey, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, ErrInvalidCredentials
+	}
+
+	return privateKey, nil
+}
+
+// Fetches access tokens for the default service account from the metadata server
+type metadataTokenSource struct{}
+
+func (metadataTokenSource) Token() (*oauth2.Token, error) {
+	body, err := getMetadata("/token")
+	if err != nil {
+		return nil, err
+	}
+
+	var token struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int    `json:"expires_in"`
+		TokenType   string `json:"token_type"`
+	}
+	if err := json.Unmarshal([]byte(body), &token); err != nil {
+		return nil, err
+	}
+
+	return &oauth2.Token{
+		AccessToken: token.AccessToken,
+		TokenType:   token.TokenType,
+		Expiry:      time.Now().Add(time.Duration(token.ExpiresIn) * time.Second),
+	}, nil
+}
+
+func getMetadata(path string) (string, error) {
+	req, err := http.NewRequest(http.MethodGet, metadataURL+path, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Metadata-Flavor", "Google")
+
+	res, err := metadataClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("metadata request failed with status %d", res.StatusCode)
+	}
+
+	b, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return "", err
+	}
+
+	return string(b), nil
+}
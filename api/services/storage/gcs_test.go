@@ -0,0 +1,199 @@
+package storage
+
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+// Simple GET case of Google's V4 signing conformance tests.
+// https://github.com/googleapis/conformance-tests/blob/main/storage/v1/v4_signatures.json
+func TestNewV4RequestConformance(t *testing.T) {
+	email := "test-iam-credentials@dummy-project-id.iam.gserviceaccount.com"
+	now := time.Date(2019, time.February, 1, 9, 0, 0, 0, time.UTC)
+
+	req := newV4Request("storage.googleapis.com", email, "test-bucket", "test-object", time.Second*10, now)
+
+	wantCanonicalRequest := "GET\n" +
+		"/test-bucket/test-object\n" +
+		"X-Goog-Algorithm=GOOG4-RSA-SHA256&X-Goog-Credential=test-iam-credentials%40dummy-project-id.iam.gserviceaccount.com%2F20190201%2Fauto%2Fstorage%2Fgoog4_request&X-Goog-Date=20190201T090000Z&X-Goog-Expires=10&X-Goog-SignedHeaders=host\n" +
+		"host:storage.googleapis.com\n" +
+		"\n" +
+		"host\n" +
+		"UNSIGNED-PAYLOAD"
+
+	wantStringToSign := "GOOG4-RSA-SHA256\n" +
+		"20190201T090000Z\n" +
+		"20190201/auto/storage/goog4_request\n" +
+		"00e2fb794ea93d7adb703edaebdd509821fcc7d4f1a79ac5c8d2b394df109320"
+
+	if req.canonicalRequest != wantCanonicalRequest {
+		t.Errorf("canonical request = %q, want %q", req.canonicalRequest, wantCanonicalRequest)
+	}
+	if req.stringToSign != wantStringToSign {
+		t.Errorf("string to sign = %q, want %q", req.stringToSign, wantStringToSign)
+	}
+}
+
+func TestNewV4RequestEscapesObjectName(t *testing.T) {
+	now := time.Date(2019, time.February, 1, 9, 0, 0, 0, time.UTC)
+
+	req := newV4Request("storage.googleapis.com", "sa@project.iam.gserviceaccount.com", "bucket", "backups/a b+c?.tar.gz", time.Minute, now)
+
+	if want := "/bucket/backups/a%20b%2Bc%3F.tar.gz"; req.path != want {
+		t.Errorf("path = %q, want %q", req.path, want)
+	}
+	if !strings.HasPrefix(req.canonicalRequest, "GET\n"+req.path+"\n") {
+		t.Errorf("canonical request %q does not sign path %q", req.canonicalRequest, req.path)
+	}
+}
+
+func newTestGCSStorage(t *testing.T, endpoint string) (*GCSStorage, *rsa.PrivateKey) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return &GCSStorage{
+		bucket:   "bucket",
+		client:   http.DefaultClient,
+		email:    "sa@project.iam.gserviceaccount.com",
+		endpoint: endpoint,
+		sign: func(payload []byte) ([]byte, error) {
+			hashed := sha256.Sum256(payload)
+			return rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, hashed[:])
+		},
+	}, key
+}
+
+func TestSignedURL(t *testing.T) {
+	s, key := newTestGCSStorage(t, GCSEndpoint)
+	now := time.Date(2019, time.February, 1, 9, 0, 0, 0, time.UTC)
+
+	signed, err := s.signedURL("backups/world.tar.gz", MaxSignedURLExpiry*2, now)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	u, err := url.Parse(signed)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := u.Query().Get("X-Goog-Expires"); got != fmt.Sprint(int64(MaxSignedURLExpiry.Seconds())) {
+		t.Errorf("X-Goog-Expires = %s, want expiry capped at %s", got, MaxSignedURLExpiry)
+	}
+
+	signature, err := hex.DecodeString(u.Query().Get("X-Goog-Signature"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req := newV4Request(u.Host, s.email, s.bucket, "backups/world.tar.gz", MaxSignedURLExpiry, now)
+	hashed := sha256.Sum256([]byte(req.stringToSign))
+	if err := rsa.VerifyPKCS1v15(&key.PublicKey, crypto.SHA256, hashed[:], signature); err != nil {
+		t.Errorf("signature does not verify: %v", err)
+	}
+
+	if _, err := s.signedURL("", time.Minute, now); err != ErrInvalidObjectName {
+		t.Errorf("signedURL() error = %v, want %v", err, ErrInvalidObjectName)
+	}
+}
+
+func TestGCSList(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/storage/v1/b/bucket/o" || r.URL.Query().Get("prefix") != "backups/" {
+			http.NotFound(w, r)
+			return
+		}
+
+		switch r.URL.Query().Get("pageToken") {
+		case "":
+			fmt.Fprint(w, `{"items":[{"name":"backups/a","size":"10","updated":"2021-01-01T00:00:00Z"}],"nextPageToken":"next"}`)
+		case "next":
+			fmt.Fprint(w, `{"items":[{"name":"backups/b","size":"20","updated":"2021-01-02T00:00:00Z"}]}`)
+		default:
+			http.Error(w, "invalid page token", http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	s, _ := newTestGCSStorage(t, server.URL)
+
+	objects, err := s.List("backups/")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(objects) != 2 || objects[0].Name != "backups/a" || objects[0].Size != 10 || objects[1].Name != "backups/b" || objects[1].Size != 20 {
+		t.Errorf("List() = %+v, want backups/a and backups/b across both pages", objects)
+	}
+}
+
+func TestGCSErrors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/storage/v1/b/bucket/o/missing":
+			http.NotFound(w, r)
+		default:
+			http.Error(w, "forbidden", http.StatusForbidden)
+		}
+	}))
+	defer server.Close()
+
+	s, _ := newTestGCSStorage(t, server.URL)
+
+	if err := s.Delete("missing"); err != ErrObjectNotFound {
+		t.Errorf("Delete() error = %v, want %v", err, ErrObjectNotFound)
+	}
+
+	if err := s.Upload("backups/a", strings.NewReader("world")); err == nil || !strings.Contains(err.Error(), "403") {
+		t.Errorf("Upload() error = %v, want status 403", err)
+	}
+}
+
+func TestMetadataTokenSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Metadata-Flavor") != "Google" {
+			http.Error(w, "missing Metadata-Flavor header", http.StatusForbidden)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/token":
+			fmt.Fprint(w, `{"access_token":"token","expires_in":3600,"token_type":"Bearer"}`)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	prev := metadataURL
+	metadataURL = server.URL
+	defer func() { metadataURL = prev }()
+
+	token, err := metadataTokenSource{}.Token()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if token.AccessToken != "token" || token.TokenType != "Bearer" {
+		t.Errorf("Token() = %+v, want Bearer token", token)
+	}
+	if until := time.Until(token.Expiry); until < time.Minute*59 || until > time.Hour {
+		t.Errorf("Token() expires in %s, want an hour", until)
+	}
+
+	if _, err := getMetadata("/email"); err == nil {
+		t.Error("getMetadata() error = nil, want status error")
+	}
+}
@@ -0,0 +1,127 @@
+package storage
+
+import (
+	"io"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Stores objects in a local directory. Meant for development where objects are linked to directly
+type LocalStorage struct {
+	directory string
+}
+
+func NewLocalStorage(directory string) (*LocalStorage, error) {
+	abs, err := filepath.Abs(directory)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := os.MkdirAll(abs, 0755); err != nil {
+		return nil, err
+	}
+
+	return &LocalStorage{abs}, nil
+}
+
+func (s *LocalStorage) List(prefix string) ([]*Object, error) {
+	entries, err := os.ReadDir(s.directory)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := []*Object{}
+
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasPrefix(entry.Name(), ".") || !strings.HasPrefix(entry.Name(), prefix) {
+			continue
+		}
+
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+
+		objects = append(objects, &Object{
+			Name:    entry.Name(),
+			Size:    info.Size(),
+			Updated: info.ModTime(),
+		})
+	}
+
+	sort.Slice(objects, func(i, j int) bool {
+		return objects[i].Name < objects[j].Name
+	})
+
+	return objects, nil
+}
+
+// Returns a file URL. Local objects do not expire
+func (s *LocalStorage) SignedURL(name string, expires time.Duration) (string, error) {
+	path, err := s.path(name)
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := os.Stat(path); err != nil {
+		if os.IsNotExist(err) {
+			return "", ErrObjectNotFound
+		}
+		return "", err
+	}
+
+	return (&url.URL{Scheme: "file", Path: path}).String(), nil
+}
+
+// Writes to a temporary file first so that partial uploads are never listed
+func (s *LocalStorage) Upload(name string, r io.Reader) error {
+	path, err := s.path(name)
+	if err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(s.directory, ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return err
+	}
+
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+func (s *LocalStorage) Delete(name string) error {
+	path, err := s.path(name)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil {
+		if os.IsNotExist(err) {
+			return ErrObjectNotFound
+		}
+		return err
+	}
+
+	return nil
+}
+
+// Objects are kept flat in the storage directory
+func (s *LocalStorage) path(name string) (string, error) {
+	if name == "" || name != filepath.Base(name) || name == "." || name == ".." {
+		return "", ErrInvalidObjectName
+	}
+	return filepath.Join(s.directory, name), nil
+}
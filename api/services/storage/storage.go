@@ -0,0 +1,69 @@
+package storage
+
+import (
+	"errors"
+	"io"
+	"time"
+
+	"go.uber.org/zap"
+
+	"agones-minecraft/config"
+)
+
+const (
+	GCSDriver   string = "gcs"
+	LocalDriver string = "local"
+)
+
+var (
+	ErrObjectNotFound    error = errors.New("object not found")
+	ErrUnsupportedDriver error = errors.New("unsupported storage driver")
+	ErrInvalidObjectName error = errors.New("invalid object name")
+)
+
+// Stored object metadata
+type Object struct {
+	Name    string
+	Size    int64
+	Updated time.Time
+}
+
+// Object storage holding world backups
+type Storage interface {
+	// Lists objects with names starting with prefix
+	List(prefix string) ([]*Object, error)
+	// Returns a URL that downloads the object until it expires
+	SignedURL(name string, expires time.Duration) (string, error)
+	// Creates or replaces an object with the reader's content
+	Upload(name string, r io.Reader) error
+	// Deletes an object. Deleting a missing object returns ErrObjectNotFound
+	Delete(name string) error
+}
+
+var storage Storage
+
+// Initializes storage for the configured driver and bucket
+func Init() {
+	s, err := New(config.GetStorageConfig())
+	if err != nil {
+		zap.L().Fatal("error initializing storage", zap.Error(err))
+	}
+	storage = s
+}
+
+// Gets initialized storage
+func Get() Storage {
+	return storage
+}
+
+// Creates storage for a driver
+func New(c *config.StorageConfig) (Storage, error) {
+	switch c.Driver {
+	case GCSDriver:
+		return NewGCSStorage(c.Bucket, c.CredentialsFile)
+	case LocalDriver:
+		return NewLocalStorage(c.LocalDirectory)
+	default:
+		return nil, ErrUnsupportedDriver
+	}
+}
@@ -3,9 +3,15 @@ package validator
 import (
 	"log"
 	"regexp"
+	"time"
 
 	"github.com/gin-gonic/gin/binding"
 	"github.com/go-playground/validator/v10"
+	"github.com/robfig/cron/v3"
+
+	"agones-minecraft/config"
+	gamev1Model "agones-minecraft/models/v1/game"
+	"agones-minecraft/services/catalog"
 )
 
 func InitV1() {
@@ -13,6 +19,21 @@ func InitV1() {
 		if err := v.RegisterValidation("mcusername", mcusername); err != nil {
 			log.Fatal(err)
 		}
+		if err := v.RegisterValidation("mcversion", mcversion); err != nil {
+			log.Fatal(err)
+		}
+		if err := v.RegisterValidation("mcservertype", mcservertype); err != nil {
+			log.Fatal(err)
+		}
+		if err := v.RegisterValidation("cron", cronSchedule); err != nil {
+			log.Fatal(err)
+		}
+		if err := v.RegisterValidation("timezone", timezone); err != nil {
+			log.Fatal(err)
+		}
+		if err := v.RegisterValidation("tier", tier); err != nil {
+			log.Fatal(err)
+		}
 	}
 }
 
@@ -24,3 +45,55 @@ func mcusername(fl validator.FieldLevel) bool {
 	}
 	return false
 }
+
+// Validates Minecraft versions. e.g. LATEST, SNAPSHOT, 1.17, 1.16.5, 1.17.1.01 (bedrock) or 21w20a (snapshot)
+func mcversion(fl validator.FieldLevel) bool {
+	version, ok := fl.Field().Interface().(string)
+	if ok {
+		reg := regexp.MustCompile(`^(LATEST|SNAPSHOT|PREVIOUS|\d+\.\d+(\.\d+){0,2}|\d{2}w\d{2}[a-z])$`)
+		return reg.MatchString(version)
+	}
+	return false
+}
+
+// Validates server types against the server type catalog
+func mcservertype(fl validator.FieldLevel) bool {
+	serverType, ok := fl.Field().Interface().(gamev1Model.ServerType)
+	if ok {
+		return catalog.Exists(serverType)
+	}
+	return false
+}
+
+// Validates standard 5 field cron schedules. e.g. 0 */6 * * *
+func cronSchedule(fl validator.FieldLevel) bool {
+	schedule, ok := fl.Field().Interface().(string)
+	if ok {
+		_, err := cron.ParseStandard(schedule)
+		return err == nil
+	}
+	return false
+}
+
+// Validates IANA time zone names. e.g. America/Chicago
+func timezone(fl validator.FieldLevel) bool {
+	name, ok := fl.Field().Interface().(string)
+	if ok {
+		// empty and Local names load zones of the API rather than named zones
+		if name == "" || name == "Local" {
+			return false
+		}
+		_, err := time.LoadLocation(name)
+		return err == nil
+	}
+	return false
+}
+
+// Validates server tier names against configured tiers
+func tier(fl validator.FieldLevel) bool {
+	name, ok := fl.Field().Interface().(string)
+	if ok {
+		_, ok = config.GetServerTier(name)
+	}
+	return ok
+}
@@ -0,0 +1,67 @@
+package workers
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+
+	"agones-minecraft/config"
+	gamev1Service "agones-minecraft/services/api/v1/game"
+)
+
+// Schedules use cron syntax so are checked every minute
+const scheduleInterval time.Duration = time.Minute
+
+// Outbox messages are applied as soon as they are committed. Polling picks up messages
+// committed by other API replicas and retries failed messages
+const outboxInterval time.Duration = time.Second * 5
+
+// Operations are tracked from GameServer changes. Unfinished operations are also checked on an interval
+// for changes that do not update their GameServer, e.g. GameServers that were never created
+const operationInterval time.Duration = time.Second * 30
+
+// Starts background workers. Requires the database, storage and Agones client to be initialized
+func Start() {
+	go every("backup retention", config.GetBackupRetentionInterval(), gamev1Service.EnforceBackupRetention)
+	go every("idle shutdown", config.GetIdleCheckInterval(), gamev1Service.StopIdleGames)
+	go every("game schedules", scheduleInterval, gamev1Service.RunSchedules)
+	go everyOrNotified("outbox", outboxInterval, gamev1Service.OutboxNotify(), gamev1Service.ProcessOutbox)
+	go every("outbox pruning", config.GetBackupRetentionInterval(), gamev1Service.PruneOutbox)
+
+	reconciler := gamev1Service.NewReconciler()
+	go reconciler.Run()
+	go every("game reconciliation", config.GetReconcileInterval(), reconciler.EnqueueAll)
+
+	tracker := gamev1Service.NewOperationTracker()
+	go tracker.Run()
+	go every("operation tracking", operationInterval, tracker.EnqueueUnfinished)
+}
+
+// Runs a job on an interval. Failed runs are logged and retried on the next interval
+func every(name string, interval time.Duration, job func() error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for range ticker.C {
+		if err := job(); err != nil {
+			zap.L().Error("error running worker", zap.String("worker", name), zap.Error(err))
+		}
+	}
+}
+
+// Runs a job on an interval and whenever notified. Failed runs are logged and retried on the next run
+func everyOrNotified(name string, interval time.Duration, notify <-chan struct{}, job func() error) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+		case <-notify:
+		}
+
+		if err := job(); err != nil {
+			zap.L().Error("error running worker", zap.String("worker", name), zap.Error(err))
+		}
+	}
+}
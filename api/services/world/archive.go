@@ -0,0 +1,279 @@
+package world
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	gamev1Model "agones-minecraft/models/v1/game"
+)
+
+type Format string
+
+const (
+	Zip   Format = "zip"
+	TarGz Format = "tar.gz"
+
+	// Upper bound on archive entries to reject archives made of many tiny files
+	MaxEntries int = 100000
+
+	javaMarker    string = "level.dat"
+	bedrockMarker string = "db"
+)
+
+var (
+	ErrUnsupportedFormat error = errors.New("unsupported world archive format. upload a .zip, .tar.gz or .tgz archive")
+	ErrInvalidArchive    error = errors.New("invalid world archive")
+	ErrUnsafeEntry       error = errors.New("world archive contains unsafe paths or links")
+	ErrTooManyEntries    error = errors.New("world archive contains too many files")
+	ErrTooLarge          error = errors.New("world archive is too large when extracted")
+	ErrMissingLevelDat   error = errors.New("java world archives must contain level.dat")
+	ErrMissingBedrockDB  error = errors.New("bedrock world archives must contain a db directory")
+)
+
+// Returns the archive format for a file name
+func FormatFromName(name string) (Format, error) {
+	lower := strings.ToLower(name)
+	switch {
+	case strings.HasSuffix(lower, ".zip"):
+		return Zip, nil
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return TarGz, nil
+	default:
+		return "", ErrUnsupportedFormat
+	}
+}
+
+type entry struct {
+	name  string
+	dir   bool
+	size  int64
+	open  func() (io.ReadCloser, error)
+	valid bool
+}
+
+// Validates a world archive for an edition and rewrites it to dst as a zip holding the world directory's
+// contents at its root. Files outside of the world directory are dropped.
+// maxSize limits the extracted size of the world
+func Normalize(src io.ReaderAt, size int64, format Format, edition gamev1Model.Edition, maxSize int64, dst io.Writer) error {
+	// first pass validates entries and finds the shallowest world directory without reading contents
+	var root string
+	var found bool
+	var total int64
+	var count int
+	rootDepth := 0
+
+	err := walk(src, size, format, func(e *entry) error {
+		count++
+		if count > MaxEntries {
+			return ErrTooManyEntries
+		}
+
+		if !e.valid {
+			return ErrUnsafeEntry
+		}
+
+		total += e.size
+		if total > maxSize {
+			return ErrTooLarge
+		}
+
+		if dir, ok := findMarker(e, edition); ok {
+			depth := 0
+			if dir != "" {
+				depth = strings.Count(dir, "/") + 1
+			}
+			if !found || depth < rootDepth {
+				root = dir
+				rootDepth = depth
+				found = true
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	if !found {
+		if edition == gamev1Model.BedrockEdition {
+			return ErrMissingBedrockDB
+		}
+		return ErrMissingLevelDat
+	}
+
+	zw := zip.NewWriter(dst)
+
+	// extracted sizes declared by zip headers are not trusted so written bytes are counted too
+	var written int64
+
+	err = walk(src, size, format, func(e *entry) error {
+		name := e.name
+		if root != "" {
+			if !strings.HasPrefix(name, root+"/") {
+				return nil
+			}
+			name = strings.TrimPrefix(name, root+"/")
+		}
+
+		if name == "" {
+			return nil
+		}
+
+		if e.dir {
+			_, err := zw.Create(name + "/")
+			return err
+		}
+
+		w, err := zw.Create(name)
+		if err != nil {
+			return err
+		}
+
+		r, err := e.open()
+		if err != nil {
+			return err
+		}
+		defer r.Close()
+
+		n, err := io.Copy(w, io.LimitReader(r, maxSize-written+1))
+		if err != nil {
+			return err
+		}
+
+		written += n
+		if written > maxSize {
+			return ErrTooLarge
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	return zw.Close()
+}
+
+// Returns the world directory of an entry marking a world for the edition
+func findMarker(e *entry, edition gamev1Model.Edition) (string, bool) {
+	if edition == gamev1Model.BedrockEdition {
+		parts := strings.Split(e.name, "/")
+		for i, part := range parts {
+			// db must be a directory. Files inside db mark it as a directory for archives without directory entries
+			if part == bedrockMarker && (i < len(parts)-1 || e.dir) {
+				return strings.Join(parts[:i], "/"), true
+			}
+		}
+		return "", false
+	}
+
+	if !e.dir && path.Base(e.name) == javaMarker {
+		dir := path.Dir(e.name)
+		if dir == "." {
+			dir = ""
+		}
+		return dir, true
+	}
+
+	return "", false
+}
+
+// Calls fn for every archive entry in order
+func walk(src io.ReaderAt, size int64, format Format, fn func(*entry) error) error {
+	switch format {
+	case Zip:
+		return walkZip(src, size, fn)
+	case TarGz:
+		return walkTarGz(src, size, fn)
+	default:
+		return ErrUnsupportedFormat
+	}
+}
+
+func walkZip(src io.ReaderAt, size int64, fn func(*entry) error) error {
+	zr, err := zip.NewReader(src, size)
+	if err != nil {
+		return ErrInvalidArchive
+	}
+
+	for _, f := range zr.File {
+		f := f
+		name, ok := cleanName(f.Name)
+		mode := f.Mode()
+
+		e := &entry{
+			name:  name,
+			dir:   mode.IsDir(),
+			size:  int64(f.UncompressedSize64),
+			open:  f.Open,
+			valid: ok && (mode.IsDir() || mode.IsRegular()),
+		}
+
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func walkTarGz(src io.ReaderAt, size int64, fn func(*entry) error) error {
+	gr, err := gzip.NewReader(io.NewSectionReader(src, 0, size))
+	if err != nil {
+		return ErrInvalidArchive
+	}
+	defer gr.Close()
+
+	tr := tar.NewReader(gr)
+
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		} else if err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+		}
+
+		// pax and gnu metadata headers are handled by the tar reader
+		name, ok := cleanName(hdr.Name)
+
+		e := &entry{
+			name:  name,
+			dir:   hdr.Typeflag == tar.TypeDir,
+			size:  hdr.Size,
+			open:  func() (io.ReadCloser, error) { return io.NopCloser(tr), nil },
+			valid: ok && (hdr.Typeflag == tar.TypeDir || hdr.Typeflag == tar.TypeReg || hdr.Typeflag == tar.TypeRegA),
+		}
+
+		if err := fn(e); err != nil {
+			return err
+		}
+	}
+}
+
+// Cleans an entry name. Absolute paths, parent directory references and backslashes are unsafe
+func cleanName(name string) (string, bool) {
+	if name == "" || strings.Contains(name, "\\") || strings.HasPrefix(name, "/") {
+		return "", false
+	}
+
+	for _, part := range strings.Split(strings.TrimSuffix(name, "/"), "/") {
+		if part == ".." {
+			return "", false
+		}
+	}
+
+	cleaned := path.Clean(name)
+	if cleaned == "." {
+		return "", true
+	}
+
+	return cleaned, true
+}
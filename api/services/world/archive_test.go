@@ -0,0 +1,332 @@
+package world
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"reflect"
+	"sort"
+	"testing"
+
+	gamev1Model "agones-minecraft/models/v1/game"
+)
+
+const testMaxSize int64 = 1 << 16
+
+// Archive entry written by the test archive builders
+type testEntry struct {
+	name    string
+	content string
+	dir     bool
+	symlink string
+}
+
+func file(name string, content string) testEntry {
+	return testEntry{name: name, content: content}
+}
+
+func dir(name string) testEntry {
+	return testEntry{name: name, dir: true}
+}
+
+func symlink(name string, target string) testEntry {
+	return testEntry{name: name, symlink: target}
+}
+
+func buildZip(t *testing.T, entries []testEntry) []byte {
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	for _, e := range entries {
+		hdr := &zip.FileHeader{Name: e.name, Method: zip.Deflate}
+		switch {
+		case e.dir:
+			hdr.SetMode(0755 | 1<<31)
+		case e.symlink != "":
+			hdr.SetMode(0777 | 1<<27)
+		default:
+			hdr.SetMode(0644)
+		}
+
+		w, err := zw.CreateHeader(hdr)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		content := e.content
+		if e.symlink != "" {
+			content = e.symlink
+		}
+		if _, err := io.WriteString(w, content); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := zw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func buildTarGz(t *testing.T, entries []testEntry) []byte {
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+
+	for _, e := range entries {
+		hdr := &tar.Header{Name: e.name, Mode: 0644, Typeflag: tar.TypeReg, Size: int64(len(e.content))}
+		switch {
+		case e.dir:
+			hdr.Typeflag, hdr.Mode, hdr.Size = tar.TypeDir, 0755, 0
+		case e.symlink != "":
+			hdr.Typeflag, hdr.Linkname, hdr.Size = tar.TypeSymlink, e.symlink, 0
+		}
+
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if hdr.Typeflag == tar.TypeReg {
+			if _, err := io.WriteString(tw, e.content); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gw.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	return buf.Bytes()
+}
+
+func build(t *testing.T, format Format, entries []testEntry) []byte {
+	if format == Zip {
+		return buildZip(t, entries)
+	}
+	return buildTarGz(t, entries)
+}
+
+// Returns the names and contents of the files in a normalized zip
+func readNormalized(t *testing.T, b []byte) map[string]string {
+	zr, err := zip.NewReader(bytes.NewReader(b), int64(len(b)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	files := map[string]string{}
+	for _, f := range zr.File {
+		if f.Mode().IsDir() {
+			continue
+		}
+
+		r, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		content, err := io.ReadAll(r)
+		r.Close()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		files[f.Name] = string(content)
+	}
+
+	return files
+}
+
+func TestNormalize(t *testing.T) {
+	bomb := string(make([]byte, testMaxSize+1))
+
+	tests := []struct {
+		name    string
+		edition gamev1Model.Edition
+		entries []testEntry
+		want    map[string]string
+		wantErr error
+	}{
+		{
+			name:    "java world at root",
+			edition: gamev1Model.JavaEdition,
+			entries: []testEntry{file("level.dat", "level"), dir("region/"), file("region/r.0.0.mca", "region")},
+			want:    map[string]string{"level.dat": "level", "region/r.0.0.mca": "region"},
+		},
+		{
+			name:    "java world in a directory",
+			edition: gamev1Model.JavaEdition,
+			entries: []testEntry{file("README.txt", "readme"), dir("world/"), file("world/level.dat", "level"), file("world/region/r.0.0.mca", "region")},
+			want:    map[string]string{"level.dat": "level", "region/r.0.0.mca": "region"},
+		},
+		{
+			name:    "nested world roots use the shallowest",
+			edition: gamev1Model.JavaEdition,
+			entries: []testEntry{file("saves/world/backup/level.dat", "old"), file("saves/world/level.dat", "level"), file("saves/other.txt", "other")},
+			want:    map[string]string{"level.dat": "level", "backup/level.dat": "old"},
+		},
+		{
+			name:    "bedrock world",
+			edition: gamev1Model.BedrockEdition,
+			entries: []testEntry{file("worlds/Bedrock level/levelname.txt", "name"), file("worlds/Bedrock level/db/CURRENT", "current")},
+			want:    map[string]string{"levelname.txt": "name", "db/CURRENT": "current"},
+		},
+		{
+			name:    "parent directory reference",
+			edition: gamev1Model.JavaEdition,
+			entries: []testEntry{file("level.dat", "level"), file("../evil", "evil")},
+			wantErr: ErrUnsafeEntry,
+		},
+		{
+			name:    "nested parent directory reference",
+			edition: gamev1Model.JavaEdition,
+			entries: []testEntry{file("world/level.dat", "level"), file("world/../../evil", "evil")},
+			wantErr: ErrUnsafeEntry,
+		},
+		{
+			name:    "absolute path",
+			edition: gamev1Model.JavaEdition,
+			entries: []testEntry{file("level.dat", "level"), file("/etc/evil", "evil")},
+			wantErr: ErrUnsafeEntry,
+		},
+		{
+			name:    "backslash path",
+			edition: gamev1Model.JavaEdition,
+			entries: []testEntry{file("level.dat", "level"), file("..\\evil", "evil")},
+			wantErr: ErrUnsafeEntry,
+		},
+		{
+			name:    "symlink",
+			edition: gamev1Model.JavaEdition,
+			entries: []testEntry{file("level.dat", "level"), symlink("region", "/etc")},
+			wantErr: ErrUnsafeEntry,
+		},
+		{
+			name:    "size bomb",
+			edition: gamev1Model.JavaEdition,
+			entries: []testEntry{file("level.dat", "level"), file("region/r.0.0.mca", bomb)},
+			wantErr: ErrTooLarge,
+		},
+		{
+			name:    "missing level.dat",
+			edition: gamev1Model.JavaEdition,
+			entries: []testEntry{file("world/region/r.0.0.mca", "region"), file("world/level.dat_old", "old")},
+			wantErr: ErrMissingLevelDat,
+		},
+		{
+			name:    "level.dat directory",
+			edition: gamev1Model.JavaEdition,
+			entries: []testEntry{dir("level.dat/")},
+			wantErr: ErrMissingLevelDat,
+		},
+		{
+			name:    "missing bedrock db",
+			edition: gamev1Model.BedrockEdition,
+			entries: []testEntry{file("levelname.txt", "name"), file("db", "not a directory")},
+			wantErr: ErrMissingBedrockDB,
+		},
+	}
+
+	for _, format := range []Format{Zip, TarGz} {
+		for _, tt := range tests {
+			t.Run(string(format)+"/"+tt.name, func(t *testing.T) {
+				src := build(t, format, tt.entries)
+
+				var dst bytes.Buffer
+				err := Normalize(bytes.NewReader(src), int64(len(src)), format, tt.edition, testMaxSize, &dst)
+				if tt.wantErr != nil {
+					if !errors.Is(err, tt.wantErr) {
+						t.Fatalf("Normalize() error = %v, want %v", err, tt.wantErr)
+					}
+					return
+				}
+				if err != nil {
+					t.Fatalf("Normalize() error = %v", err)
+				}
+
+				if got := readNormalized(t, dst.Bytes()); !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("Normalize() files = %v, want %v", got, tt.want)
+				}
+			})
+		}
+	}
+}
+
+func TestNormalizeTooManyEntries(t *testing.T) {
+	entries := []testEntry{file("level.dat", "level")}
+	for i := 0; i < MaxEntries; i++ {
+		entries = append(entries, dir("region/"))
+	}
+
+	src := buildTarGz(t, entries)
+
+	err := Normalize(bytes.NewReader(src), int64(len(src)), TarGz, gamev1Model.JavaEdition, testMaxSize, io.Discard)
+	if err != ErrTooManyEntries {
+		t.Errorf("Normalize() error = %v, want %v", err, ErrTooManyEntries)
+	}
+}
+
+func TestNormalizeInvalidArchive(t *testing.T) {
+	src := []byte("not an archive")
+
+	for _, format := range []Format{Zip, TarGz} {
+		err := Normalize(bytes.NewReader(src), int64(len(src)), format, gamev1Model.JavaEdition, testMaxSize, io.Discard)
+		if !errors.Is(err, ErrInvalidArchive) {
+			t.Errorf("Normalize(%s) error = %v, want %v", format, err, ErrInvalidArchive)
+		}
+	}
+}
+
+func TestCleanName(t *testing.T) {
+	tests := []struct {
+		name   string
+		want   string
+		wantOk bool
+	}{
+		{"world/level.dat", "world/level.dat", true},
+		{"world/", "world", true},
+		{"./world/level.dat", "world/level.dat", true},
+		{"world//region/./r.0.0.mca", "world/region/r.0.0.mca", true},
+		{"./", "", true},
+		{"", "", false},
+		{"/etc/passwd", "", false},
+		{"../evil", "", false},
+		{"world/../../evil", "", false},
+		{"world/..", "", false},
+		{"world\\..\\evil", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := cleanName(tt.name)
+			if got != tt.want || ok != tt.wantOk {
+				t.Errorf("cleanName(%q) = %q, %v, want %q, %v", tt.name, got, ok, tt.want, tt.wantOk)
+			}
+		})
+	}
+}
+
+func TestFormatFromName(t *testing.T) {
+	tests := map[string]Format{"world.zip": Zip, "World.ZIP": Zip, "world.tar.gz": TarGz, "world.tgz": TarGz}
+
+	names := make([]string, 0, len(tests))
+	for name := range tests {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if got, err := FormatFromName(name); err != nil || got != tests[name] {
+			t.Errorf("FormatFromName(%q) = %s, %v, want %s", name, got, err, tests[name])
+		}
+	}
+
+	if _, err := FormatFromName("world.rar"); err != ErrUnsupportedFormat {
+		t.Errorf("FormatFromName() error = %v, want %v", err, ErrUnsupportedFormat)
+	}
+}
@@ -10,6 +10,7 @@ require (
 	github.com/onsi/gomega v1.11.0
 	github.com/smartystreets/assertions v1.0.1 // indirect
 	golang.org/x/oauth2 v0.0.0-20210413134643-5e61552d6c78
+	golang.org/x/time v0.0.0-20200630173020-3af7569d3a1e
 	google.golang.org/api v0.45.0
 	gopkg.in/ini.v1 v1.51.1 // indirect
 	k8s.io/api v0.20.2
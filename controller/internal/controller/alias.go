@@ -0,0 +1,65 @@
+package controller
+
+import (
+	"context"
+	"strings"
+
+	agonesv1 "agones.dev/agones/pkg/apis/agones/v1"
+	schm "github.com/saulmaldonado/agones-minecraft/controller/internal/controller/scheme"
+	mcDns "github.com/saulmaldonado/agones-minecraft/controller/internal/dns"
+)
+
+// Publishes an alias SRV record for Allocated GameServers with an agones-mc/alias annotation
+// (usually set through GameServerAllocation metadata) and removes it once the GameServer leaves Allocated
+func (r *GameServerReconciler) ReconcileAlias(ctx context.Context, gs *agonesv1.GameServer) error {
+	domain, domainFound := getDomainAnnotationOrLabel(gs)
+	if !domainFound {
+		return nil
+	}
+
+	alias, aliasFound := getAliasAnnotation(gs)
+	published, publishedFound := getAnnotation(ExternalAliasAnnotation, gs)
+
+	wanted := aliasFound && schm.IsAllocated(gs) && !schm.IsResourceDeleted(gs)
+	aliasName := mcDns.JoinARecordName(domain, alias)
+
+	log := r.logger(gs, domain)
+
+	if publishedFound && (!wanted || published != aliasName) {
+		publishedAlias := strings.TrimSuffix(published, "."+domain)
+
+		if err := r.Dns.RemoveGameServerAliasDns(domain, publishedAlias, gs); err != nil {
+			if err := r.Dns.IgnoreClientError(err); err != nil {
+				return err
+			}
+			log.Error(err, "Error removing alias DNS", LogKeyRecord, mcDns.JoinSrvRecordName(domain, publishedAlias))
+		} else {
+			log.Info("Alias DNS record removed", LogKeyRecord, mcDns.JoinSrvRecordName(domain, publishedAlias))
+		}
+
+		removeAnnotation(ExternalAliasAnnotation, gs)
+
+		if err := r.Update(ctx, gs); err != nil {
+			return err
+		}
+
+		publishedFound = false
+	}
+
+	if wanted && !publishedFound {
+		if err := r.Dns.SetGameServerAliasDns(domain, alias, gs); err != nil {
+			log.Error(err, "Error setting alias DNS", LogKeyRecord, mcDns.JoinSrvRecordName(domain, alias))
+			return r.Dns.IgnoreClientError(err)
+		}
+
+		setExternalAliasAnnotation(aliasName, gs)
+
+		if err := r.Update(ctx, gs); err != nil {
+			return err
+		}
+
+		log.Info("New alias DNS record set", LogKeyRecord, mcDns.JoinSrvRecordName(domain, alias))
+	}
+
+	return nil
+}
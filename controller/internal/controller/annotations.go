@@ -9,9 +9,11 @@ import (
 )
 
 const (
-	AnnotationPrefix      string = "agones-mc"
-	DomainAnnotation      string = "domain"
-	ExternalDnsAnnotation string = "externalDNS"
+	AnnotationPrefix        string = "agones-mc"
+	DomainAnnotation        string = "domain"
+	ExternalDnsAnnotation   string = "externalDNS"
+	AliasAnnotation         string = "alias"
+	ExternalAliasAnnotation string = "externalAlias"
 )
 
 func getDomainAnnotationOrLabel(obj client.Object) (string, bool) {
@@ -32,6 +34,20 @@ func setExternalDnsAnnotation(recordName string, obj client.Object) string {
 	return recordName
 }
 
+func getAliasAnnotation(obj client.Object) (string, bool) {
+	if alias, found := getAnnotation(AliasAnnotation, obj); found && dns.IsDnsName(alias) {
+		return strings.TrimSuffix(alias, "."), true
+	}
+
+	return "", false
+}
+
+func setExternalAliasAnnotation(recordName string, obj client.Object) string {
+	recordName = dns.EnsureTrailingDot(recordName)
+	setAnnotation(ExternalAliasAnnotation, recordName, obj)
+	return recordName
+}
+
 func findExternalDnsAnnotation(obj client.Object) bool {
 	_, ok := getAnnotation(ExternalDnsAnnotation, obj)
 	return ok
@@ -64,7 +80,7 @@ func getLabel(suffix string, obj client.Object) (string, bool) {
 }
 
 func setAnnotation(suffix string, value string, obj client.Object) {
-	key := fmt.Sprintf("%s/%s", AnnotationPrefix, ExternalDnsAnnotation)
+	key := fmt.Sprintf("%s/%s", AnnotationPrefix, suffix)
 	annotations := obj.GetAnnotations()
 
 	if annotations == nil {
@@ -74,3 +90,11 @@ func setAnnotation(suffix string, value string, obj client.Object) {
 
 	annotations[key] = value
 }
+
+func removeAnnotation(suffix string, obj client.Object) {
+	key := fmt.Sprintf("%s/%s", AnnotationPrefix, suffix)
+	annotations := obj.GetAnnotations()
+
+	delete(annotations, key)
+	obj.SetAnnotations(annotations)
+}
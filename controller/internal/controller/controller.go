@@ -13,9 +13,19 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 )
 
+// Structured log keys used across reconciler log calls
+const (
+	LogKeyGVK       string = "gvk"
+	LogKeyNamespace string = "namespace"
+	LogKeyName      string = "name"
+	LogKeyDomain    string = "domain"
+	LogKeyRecord    string = "record"
+)
+
 type DnsReconciler struct {
 	client.Client
 	Scheme *runtime.Scheme
@@ -31,13 +41,15 @@ func (r *DnsReconciler) ReconcileDns(ctx context.Context, req reconcile.Request,
 	dnsExists := findExternalDnsAnnotation(obj)
 	domain, domainFound := getDomainAnnotationOrLabel(obj)
 
+	log := r.logger(obj, domain).WithValues(LogKeyRecord, recordName(domain, obj))
+
 	if dnsExists {
 		if schm.IsResourceDeleted(obj) && findFinalizer(obj) {
 
 			if err := r.cleanUpResource(domain, obj); err != nil {
-				r.Log.Error(err, "Error cleaning up resource DNS", "Resource", schm.GVKString(obj), "Name", obj.GetName())
+				log.Error(err, "Error cleaning up resource DNS")
 			} else {
-				r.Log.Info("DNS record removed", "Resource", schm.GVKString(obj), "Name", obj.GetName())
+				log.Info("DNS record removed")
 			}
 
 			if err := r.deleteResource(ctx, obj); err != nil {
@@ -55,23 +67,40 @@ func (r *DnsReconciler) ReconcileDns(ctx context.Context, req reconcile.Request,
 		}
 
 		if err := r.setupResource(ctx, domain, obj); err != nil {
-			r.Log.Error(err, "Error setting Resource DNS", "Resource", schm.GVKString(obj), "Name", obj.GetName())
+			log.Error(err, "Error setting Resource DNS")
 			return reconcile.Result{}, r.Dns.IgnoreClientError(err)
 		}
 
-		r.Log.Info("New DNS record set", "Resource", schm.GVKString(obj), "Name", obj.GetName())
+		log.Info("New DNS record set")
 		return reconcile.Result{}, nil
 	}
 
-	r.Log.Info("No domain annotation/label or is invalid domain name", "Resource", schm.GVKString(obj), "Name", obj.GetName())
+	log.Info("No domain annotation/label or is invalid domain name")
 	return reconcile.Result{}, nil
 }
 
+// Returns a logger with the resource's gvk, namespace, name and domain as structured fields
+func (r *DnsReconciler) logger(obj client.Object, domain string) logr.Logger {
+	gvk := schm.GVKString(obj)
+	if r.Scheme != nil {
+		if kind, err := apiutil.GVKForObject(obj, r.Scheme); err == nil {
+			gvk = kind.String()
+		}
+	}
+
+	return r.Log.WithValues(
+		LogKeyGVK, gvk,
+		LogKeyNamespace, obj.GetNamespace(),
+		LogKeyName, obj.GetName(),
+		LogKeyDomain, domain,
+	)
+}
+
 func (r *DnsReconciler) getResource(ctx context.Context, namespacedName types.NamespacedName, obj client.Object) error {
 	err := r.Get(ctx, namespacedName, obj)
 
 	if err != nil && errors.IsNotFound(err) {
-		r.Log.Info("Could not find resource", "Name", namespacedName.String())
+		r.Log.Info("Could not find resource", LogKeyNamespace, namespacedName.Namespace, LogKeyName, namespacedName.Name)
 	}
 
 	return err
@@ -116,3 +145,16 @@ func (r *DnsReconciler) deleteResource(ctx context.Context, obj client.Object) e
 	removeFinalizer(obj)
 	return r.Update(ctx, obj)
 }
+
+// Returns the name of the DNS record managed for the resource
+func recordName(hostname string, obj client.Object) string {
+	if hostname == "" {
+		return ""
+	}
+
+	if _, ok := obj.(*agonesv1.GameServer); ok {
+		return mcDns.JoinSrvRecordName(hostname, obj.GetName())
+	}
+
+	return mcDns.JoinARecordName(hostname, obj.GetName())
+}
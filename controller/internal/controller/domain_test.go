@@ -0,0 +1,109 @@
+package controller_test
+
+import (
+	"context"
+	"time"
+
+	agonesv1 "agones.dev/agones/pkg/apis/agones/v1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var _ = Describe("Domain annotations and labels", func() {
+	const (
+		Timeout  = time.Second * 10
+		Interval = time.Millisecond * 250
+	)
+
+	var (
+		GameServerNodeName string          = "mc-node"
+		ctx                context.Context = context.Background()
+	)
+
+	hasRecord := func(name, data string) func() bool {
+		return func() bool {
+			return FakeDns.HasRecord(name, data)
+		}
+	}
+
+	Context("When a GameServer only has a domain label", func() {
+		It("Should use the label domain", func() {
+			gameServerKey := types.NamespacedName{Namespace: metav1.NamespaceDefault, Name: "mc-server-label"}
+
+			gs := newTestGameServer(gameServerKey.Name, GameServerNodeName, 7200, nil, map[string]string{"agones-mc/domain": "saulmaldonado.me"})
+			Expect(testClient.Create(ctx, gs)).Should(Succeed())
+
+			Eventually(getAnnotation(ctx, gameServerKey, &agonesv1.GameServer{}, "agones-mc/externalDNS"), Timeout, Interval).Should(Equal("mc-server-label.saulmaldonado.me."))
+			Eventually(hasRecord("_minecraft._tcp.mc-server-label.saulmaldonado.me.", "0 0 7200 mc-node.saulmaldonado.me."), Timeout, Interval).Should(BeTrue())
+
+			deleteAndWait(ctx, gameServerKey, &agonesv1.GameServer{})
+		})
+	})
+
+	Context("When a GameServer has both a domain annotation and label", func() {
+		It("Should prefer the annotation domain", func() {
+			gameServerKey := types.NamespacedName{Namespace: metav1.NamespaceDefault, Name: "mc-server-both"}
+
+			gs := newTestGameServer(gameServerKey.Name, GameServerNodeName, 7201,
+				map[string]string{"agones-mc/domain": "annotation.saulmaldonado.me"},
+				map[string]string{"agones-mc/domain": "label.saulmaldonado.me"},
+			)
+			Expect(testClient.Create(ctx, gs)).Should(Succeed())
+
+			Eventually(getAnnotation(ctx, gameServerKey, &agonesv1.GameServer{}, "agones-mc/externalDNS"), Timeout, Interval).Should(Equal("mc-server-both.annotation.saulmaldonado.me."))
+			Eventually(hasRecord("_minecraft._tcp.mc-server-both.annotation.saulmaldonado.me.", "0 0 7201 mc-node.annotation.saulmaldonado.me."), Timeout, Interval).Should(BeTrue())
+
+			_, found := FakeDns.Record("_minecraft._tcp.mc-server-both.label.saulmaldonado.me.")
+			Expect(found).Should(BeFalse())
+
+			deleteAndWait(ctx, gameServerKey, &agonesv1.GameServer{})
+		})
+	})
+
+	Context("When a GameServer has an invalid domain annotation", func() {
+		It("Should fall back to the label domain", func() {
+			gameServerKey := types.NamespacedName{Namespace: metav1.NamespaceDefault, Name: "mc-server-invalid"}
+
+			gs := newTestGameServer(gameServerKey.Name, GameServerNodeName, 7202,
+				map[string]string{"agones-mc/domain": "not a domain!"},
+				map[string]string{"agones-mc/domain": "saulmaldonado.me"},
+			)
+			Expect(testClient.Create(ctx, gs)).Should(Succeed())
+
+			Eventually(getAnnotation(ctx, gameServerKey, &agonesv1.GameServer{}, "agones-mc/externalDNS"), Timeout, Interval).Should(Equal("mc-server-invalid.saulmaldonado.me."))
+			Eventually(hasRecord("_minecraft._tcp.mc-server-invalid.saulmaldonado.me.", "0 0 7202 mc-node.saulmaldonado.me."), Timeout, Interval).Should(BeTrue())
+
+			deleteAndWait(ctx, gameServerKey, &agonesv1.GameServer{})
+		})
+	})
+
+	Context("When a GameServer has no domain", func() {
+		It("Should not create a DNS record", func() {
+			gameServerKey := types.NamespacedName{Namespace: metav1.NamespaceDefault, Name: "mc-server-no-domain"}
+
+			gs := newTestGameServer(gameServerKey.Name, GameServerNodeName, 7203, nil, nil)
+			Expect(testClient.Create(ctx, gs)).Should(Succeed())
+
+			Consistently(getAnnotation(ctx, gameServerKey, &agonesv1.GameServer{}, "agones-mc/externalDNS"), time.Second*2, Interval).Should(BeEmpty())
+
+			Expect(testClient.Delete(ctx, gs)).Should(Succeed())
+		})
+	})
+
+	Context("When a Node has a domain annotation", func() {
+		It("Should use the annotation domain", func() {
+			nodeKey := types.NamespacedName{Name: "mc-node-annotation"}
+
+			Expect(testClient.Create(ctx, newTestNode(nodeKey.Name, map[string]string{"agones-mc/domain": "saulmaldonado.me"}, nil))).Should(Succeed())
+			Eventually(setNodeAddresses(ctx, nodeKey.Name, corev1.NodeAddress{Type: corev1.NodeExternalIP, Address: "203.0.113.40"}), Timeout, Interval).Should(Succeed())
+
+			Eventually(getAnnotation(ctx, nodeKey, &corev1.Node{}, "agones-mc/externalDNS"), Timeout, Interval).Should(Equal("mc-node-annotation.saulmaldonado.me."))
+			Eventually(hasRecord("mc-node-annotation.saulmaldonado.me.", "203.0.113.40"), Timeout, Interval).Should(BeTrue())
+
+			deleteAndWait(ctx, nodeKey, &corev1.Node{})
+		})
+	})
+})
@@ -17,6 +17,16 @@ type GameServerReconciler struct {
 
 func (r *GameServerReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
 	gs := agonesv1.GameServer{}
+
+	if err := r.getResource(ctx, req.NamespacedName, &gs); err != nil {
+		return reconcile.Result{}, client.IgnoreNotFound(err)
+	}
+
+	// aliases are reconciled first so they are removed before the finalizer is released on deletion
+	if err := r.ReconcileAlias(ctx, &gs); err != nil {
+		return reconcile.Result{}, err
+	}
+
 	return r.ReconcileDns(ctx, req, &gs)
 }
 
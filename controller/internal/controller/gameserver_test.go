@@ -92,13 +92,8 @@ var _ = Describe("GameServer Controller", func() {
 			By("Checking mock DNS store for records with correct GameServer name and domain name")
 
 			Eventually(func() bool {
-				for _, record := range FakeDns.DnsRecords {
-					if record == "_minecraft._tcp.mc-server.saulmaldonado.me. 0 0 7000 mc-node.saulmaldonado.me." {
-						return true
-					}
-				}
-				return false
-			}).Should(BeTrue())
+				return FakeDns.HasRecord("_minecraft._tcp.mc-server.saulmaldonado.me.", "0 0 7000 mc-node.saulmaldonado.me.")
+			}, Timeout, Interval).Should(BeTrue())
 		})
 	})
 
@@ -129,15 +124,208 @@ var _ = Describe("GameServer Controller", func() {
 
 			By("Checking mock DNS store for DNS records")
 			Eventually(func() bool {
-				for _, record := range FakeDns.DnsRecords {
-					if record == "_minecraft._tcp.mc-server.saulmaldonado.me. 0 0 7000 mc-node.saulmaldonado.me." {
-						return true
-					}
+				_, found := FakeDns.Record("_minecraft._tcp.mc-server.saulmaldonado.me.")
+				return found
+			}, Timeout, Interval).Should(BeFalse())
+
+		})
+	})
+
+	Context("When allocating a GameServer with an alias", func() {
+		var (
+			AliasGameServerName string = "mc-server-alias"
+			AliasGameServerPort int32  = 7001
+		)
+
+		hasAliasRecord := func() bool {
+			return FakeDns.HasRecord("_minecraft._tcp.event-42.saulmaldonado.me.", "0 0 7001 mc-node.saulmaldonado.me.")
+		}
+
+		setState := func(key types.NamespacedName, state agonesv1.GameServerState) func() error {
+			return func() error {
+				gs := &agonesv1.GameServer{}
+				if err := testClient.Get(ctx, key, gs); err != nil {
+					return err
 				}
-				return false
-			}).Should(BeFalse())
+				gs.Status.State = state
+				return testClient.Update(ctx, gs)
+			}
+		}
+
+		gameServerKey := types.NamespacedName{Namespace: metav1.NamespaceDefault, Name: AliasGameServerName}
+
+		It("Should create an alias DNS record once Allocated", func() {
+			By("creating a new Ready GameServer with an alias annotation")
 
+			gs := &agonesv1.GameServer{
+				Status: agonesv1.GameServerStatus{State: agonesv1.GameServerStateReady,
+					NodeName: GameServerNodeName,
+					Ports: []agonesv1.GameServerStatusPort{
+						{Name: "mc", Port: AliasGameServerPort},
+					},
+				},
+				TypeMeta: metav1.TypeMeta{
+					Kind:       "GameServer",
+					APIVersion: agonesv1.SchemeGroupVersion.String(),
+				},
+				ObjectMeta: metav1.ObjectMeta{
+					Annotations: map[string]string{
+						"agones-mc/domain": "saulmaldonado.me",
+						"agones-mc/alias":  "event-42",
+					},
+					Name:      AliasGameServerName,
+					Namespace: metav1.NamespaceDefault,
+				},
+				Spec: agonesv1.GameServerSpec{
+					Container: GameServerContainer,
+					Ports: []agonesv1.GameServerPort{
+						{
+							Name:          "mc",
+							PortPolicy:    "Dynamic",
+							Container:     &GameServerContainer,
+							ContainerPort: 25565,
+							Protocol:      "TCP",
+						},
+					},
+					Template: v1.PodTemplateSpec{
+						Spec: v1.PodSpec{
+							Containers: []v1.Container{
+								{
+									Name:  GameServerContainer,
+									Image: "itzg/minecraft-server",
+								},
+							},
+						},
+					},
+				},
+			}
+
+			Expect(testClient.Create(ctx, gs)).Should(Succeed())
+
+			Eventually(func() bool {
+				createdGameServer := &agonesv1.GameServer{}
+				if err := testClient.Get(ctx, gameServerKey, createdGameServer); err != nil {
+					return false
+				}
+				return createdGameServer.Annotations["agones-mc/externalDNS"] == "mc-server-alias.saulmaldonado.me."
+			}, Timeout, Interval).Should(BeTrue())
+
+			By("Checking that Ready GameServers have no alias record")
+			Expect(hasAliasRecord()).Should(BeFalse())
+
+			By("Allocating the GameServer")
+			Eventually(setState(gameServerKey, agonesv1.GameServerStateAllocated), Timeout, Interval).Should(Succeed())
+
+			By("Checking for agones-mc/externalAlias annotation")
+			Eventually(func() bool {
+				allocatedGameServer := &agonesv1.GameServer{}
+				if err := testClient.Get(ctx, gameServerKey, allocatedGameServer); err != nil {
+					return false
+				}
+				return allocatedGameServer.Annotations["agones-mc/externalAlias"] == "event-42.saulmaldonado.me."
+			}, Timeout, Interval).Should(BeTrue())
+
+			By("Checking mock DNS store for the alias record")
+			Eventually(hasAliasRecord, Timeout, Interval).Should(BeTrue())
+		})
+
+		It("Should remove the alias DNS record when leaving Allocated", func() {
+			By("Shutting down the GameServer")
+			Eventually(setState(gameServerKey, agonesv1.GameServerStateShutdown), Timeout, Interval).Should(Succeed())
+
+			By("Checking mock DNS store for the alias record")
+			Eventually(hasAliasRecord, Timeout, Interval).Should(BeFalse())
+
+			By("Checking that agones-mc/externalAlias annotation is removed")
+			Eventually(func() bool {
+				shutdownGameServer := &agonesv1.GameServer{}
+				if err := testClient.Get(ctx, gameServerKey, shutdownGameServer); err != nil {
+					return false
+				}
+				_, found := shutdownGameServer.Annotations["agones-mc/externalAlias"]
+				return found
+			}, Timeout, Interval).Should(BeFalse())
+
+			By("Deleting GameServer")
+			gs := &agonesv1.GameServer{}
+			Expect(testClient.Get(ctx, gameServerKey, gs)).Should(Succeed())
+			Expect(testClient.Delete(ctx, gs)).Should(Succeed())
+
+			Eventually(func() error {
+				return testClient.Get(ctx, gameServerKey, &agonesv1.GameServer{})
+			}, Timeout, Interval).ShouldNot(Succeed())
+		})
+	})
+	// records are only set when a GameServer is first published and are removed by their current data
+	Context("When a GameServer is rescheduled", func() {
+		It("Should keep the DNS record pointing to the original Node and port", func() {
+			gameServerKey := types.NamespacedName{Namespace: metav1.NamespaceDefault, Name: "mc-server-moved"}
+			recordName := "_minecraft._tcp.mc-server-moved.saulmaldonado.me."
+
+			By("creating a new GameServer")
+			gs := newTestGameServer(gameServerKey.Name, GameServerNodeName, 7002, map[string]string{"agones-mc/domain": "saulmaldonado.me"}, nil)
+			Expect(testClient.Create(ctx, gs)).Should(Succeed())
+
+			Eventually(func() bool {
+				return FakeDns.HasRecord(recordName, "0 0 7002 mc-node.saulmaldonado.me.")
+			}, Timeout, Interval).Should(BeTrue())
+
+			By("Moving the GameServer to a different Node and port")
+			Eventually(func() error {
+				gs := &agonesv1.GameServer{}
+				if err := testClient.Get(ctx, gameServerKey, gs); err != nil {
+					return err
+				}
+				gs.Status.NodeName = "mc-node-2"
+				gs.Status.Ports[0].Port = 7003
+				return testClient.Update(ctx, gs)
+			}, Timeout, Interval).Should(Succeed())
+
+			By("Checking that the record is not updated")
+			Consistently(func() bool {
+				return FakeDns.HasRecord(recordName, "0 0 7002 mc-node.saulmaldonado.me.")
+			}, time.Second*2, Interval).Should(BeTrue())
+
+			By("Deleting GameServer")
+			deleteAndWait(ctx, gameServerKey, &agonesv1.GameServer{})
+
+			By("Checking that the record for the original Node and port is left behind")
+			Consistently(func() bool {
+				return FakeDns.HasRecord(recordName, "0 0 7002 mc-node.saulmaldonado.me.")
+			}, time.Second*2, Interval).Should(BeTrue())
 		})
 	})
 
+	// SRV records are always published under _tcp, whatever the GameServer port's protocol
+	Context("When creating a Bedrock GameServer", func() {
+		It("Should create a _tcp DNS record for the UDP port", func() {
+			gameServerKey := types.NamespacedName{Namespace: metav1.NamespaceDefault, Name: "mc-bedrock"}
+
+			By("creating a new GameServer with a UDP port")
+			gs := newTestGameServer(gameServerKey.Name, GameServerNodeName, 7004, map[string]string{"agones-mc/domain": "saulmaldonado.me"}, nil)
+			gs.Spec.Ports[0].ContainerPort = 19132
+			gs.Spec.Ports[0].Protocol = v1.ProtocolUDP
+			gs.Spec.Template.Spec.Containers[0].Image = "itzg/minecraft-bedrock-server"
+			Expect(testClient.Create(ctx, gs)).Should(Succeed())
+
+			By("Checking for agones-mc/externalDNS annotation")
+			Eventually(getAnnotation(ctx, gameServerKey, &agonesv1.GameServer{}, "agones-mc/externalDNS"), Timeout, Interval).Should(Equal("mc-bedrock.saulmaldonado.me."))
+
+			By("Checking mock DNS store for the record")
+			Eventually(func() bool {
+				return FakeDns.HasRecord("_minecraft._tcp.mc-bedrock.saulmaldonado.me.", "0 0 7004 mc-node.saulmaldonado.me.")
+			}, Timeout, Interval).Should(BeTrue())
+
+			_, found := FakeDns.Record("_minecraft._udp.mc-bedrock.saulmaldonado.me.")
+			Expect(found).Should(BeFalse())
+
+			By("Deleting GameServer")
+			deleteAndWait(ctx, gameServerKey, &agonesv1.GameServer{})
+
+			Eventually(func() bool {
+				_, found := FakeDns.Record("_minecraft._tcp.mc-bedrock.saulmaldonado.me.")
+				return found
+			}, Timeout, Interval).Should(BeFalse())
+		})
+	})
 })
@@ -43,6 +43,9 @@ var _ = Describe("Node Controller", func() {
 
 			Expect(testClient.Create(ctx, node)).Should(Succeed())
 
+			By("Setting the Node's external IP")
+			Eventually(setNodeAddresses(ctx, NodeName, corev1.NodeAddress{Type: corev1.NodeExternalIP, Address: "203.0.113.10"}), Timeout, Interval).Should(Succeed())
+
 			By("Checking for agones-mc/externalDNS annotation")
 			createdNode := &corev1.Node{}
 			nodeKey := types.NamespacedName{Namespace: corev1.NamespaceDefault, Name: NodeName}
@@ -53,6 +56,11 @@ var _ = Describe("Node Controller", func() {
 				}
 				return createdNode.Annotations["agones-mc/externalDNS"] == "mc-node.saulmaldonado.me."
 			}, Timeout, Interval).Should(BeTrue())
+
+			By("Checking mock DNS store for DNS record")
+			Eventually(func() bool {
+				return FakeDns.HasRecord("mc-node.saulmaldonado.me.", "203.0.113.10")
+			}, Timeout, Interval).Should(BeTrue())
 		})
 
 		It("Should remove DNS records for deleted Nodes", func() {
@@ -84,12 +92,128 @@ var _ = Describe("Node Controller", func() {
 
 			By("Checking mock DNS store for DNS record")
 			Eventually(func() bool {
-				for _, record := range FakeDns.DnsRecords {
-					if record == "mc-node.saulmaldonado.me." {
-						return true
-					}
-				}
-				return false
+				_, found := FakeDns.Record("mc-node.saulmaldonado.me.")
+				return found
+			}, Timeout, Interval).Should(BeFalse())
+		})
+	})
+
+	// records are only set when a Node is first published and are removed by their current data
+	Context("When a Node's external IP changes", func() {
+		It("Should keep the A record for the original IP", func() {
+			nodeKey := types.NamespacedName{Name: "mc-node-ip-change"}
+			recordName := "mc-node-ip-change.saulmaldonado.me."
+
+			By("creating a new Node with an external IP")
+			Expect(testClient.Create(ctx, newTestNode(nodeKey.Name, nil, map[string]string{"agones-mc/domain": "saulmaldonado.me"}))).Should(Succeed())
+			Eventually(setNodeAddresses(ctx, nodeKey.Name, corev1.NodeAddress{Type: corev1.NodeExternalIP, Address: "203.0.113.20"}), Timeout, Interval).Should(Succeed())
+
+			Eventually(func() bool {
+				return FakeDns.HasRecord(recordName, "203.0.113.20")
+			}, Timeout, Interval).Should(BeTrue())
+
+			By("Changing the Node's external IP")
+			Eventually(setNodeAddresses(ctx, nodeKey.Name, corev1.NodeAddress{Type: corev1.NodeExternalIP, Address: "203.0.113.21"}), Timeout, Interval).Should(Succeed())
+
+			By("Checking that the record is not updated")
+			Consistently(func() bool {
+				return FakeDns.HasRecord(recordName, "203.0.113.20")
+			}, time.Second*2, Interval).Should(BeTrue())
+
+			By("Deleting Node")
+			deleteAndWait(ctx, nodeKey, &corev1.Node{})
+
+			By("Checking that the record for the original IP is left behind")
+			Consistently(func() bool {
+				return FakeDns.HasRecord(recordName, "203.0.113.20")
+			}, time.Second*2, Interval).Should(BeTrue())
+		})
+	})
+
+	Context("When a Node has no external IP", func() {
+		It("Should wait for an external IP before creating the A record", func() {
+			nodeKey := types.NamespacedName{Name: "mc-node-no-ip"}
+			recordName := "mc-node-no-ip.saulmaldonado.me."
+
+			By("creating a new Node with only an internal IP")
+			Expect(testClient.Create(ctx, newTestNode(nodeKey.Name, nil, map[string]string{"agones-mc/domain": "saulmaldonado.me"}))).Should(Succeed())
+			Eventually(setNodeAddresses(ctx, nodeKey.Name, corev1.NodeAddress{Type: corev1.NodeInternalIP, Address: "10.0.0.5"}), Timeout, Interval).Should(Succeed())
+
+			By("Checking that no record or annotation is set")
+			Consistently(func() bool {
+				_, found := FakeDns.Record(recordName)
+				return found
+			}, time.Second*2, Interval).Should(BeFalse())
+
+			Expect(getAnnotation(ctx, nodeKey, &corev1.Node{}, "agones-mc/externalDNS")()).Should(BeEmpty())
+
+			By("Adding an external IP")
+			Eventually(setNodeAddresses(ctx, nodeKey.Name,
+				corev1.NodeAddress{Type: corev1.NodeInternalIP, Address: "10.0.0.5"},
+				corev1.NodeAddress{Type: corev1.NodeExternalIP, Address: "203.0.113.30"},
+			), Timeout, Interval).Should(Succeed())
+
+			Eventually(func() bool {
+				return FakeDns.HasRecord(recordName, "203.0.113.30")
+			}, Timeout, Interval).Should(BeTrue())
+
+			Eventually(getAnnotation(ctx, nodeKey, &corev1.Node{}, "agones-mc/externalDNS"), Timeout, Interval).Should(Equal(recordName))
+
+			By("Deleting Node")
+			deleteAndWait(ctx, nodeKey, &corev1.Node{})
+		})
+	})
+	Context("When a Node only has an external DNS name", func() {
+		It("Should create a CNAME record to the external DNS name", func() {
+			nodeKey := types.NamespacedName{Name: "mc-node-hostname"}
+			recordName := "mc-node-hostname.saulmaldonado.me."
+
+			By("creating a new Node with an external DNS name")
+			Expect(testClient.Create(ctx, newTestNode(nodeKey.Name, nil, map[string]string{"agones-mc/domain": "saulmaldonado.me"}))).Should(Succeed())
+			Eventually(setNodeAddresses(ctx, nodeKey.Name, corev1.NodeAddress{Type: corev1.NodeExternalDNS, Address: "node-1.compute.example.com"}), Timeout, Interval).Should(Succeed())
+
+			Eventually(func() bool {
+				return FakeDns.HasRecord(recordName, "node-1.compute.example.com.")
+			}, Timeout, Interval).Should(BeTrue())
+
+			Eventually(getAnnotation(ctx, nodeKey, &corev1.Node{}, "agones-mc/externalDNS"), Timeout, Interval).Should(Equal(recordName))
+
+			By("Deleting Node")
+			deleteAndWait(ctx, nodeKey, &corev1.Node{})
+
+			Eventually(func() bool {
+				_, found := FakeDns.Record(recordName)
+				return found
+			}, Timeout, Interval).Should(BeFalse())
+		})
+	})
+
+	Context("When a Node has a load balancer IP annotation", func() {
+		It("Should create an A record to the load balancer IP", func() {
+			nodeKey := types.NamespacedName{Name: "mc-node-lb"}
+			recordName := "mc-node-lb.saulmaldonado.me."
+
+			By("creating a new Node behind a load balancer")
+			node := newTestNode(nodeKey.Name,
+				map[string]string{"agones-mc/loadBalancerIP": "198.51.100.7"},
+				map[string]string{"agones-mc/domain": "saulmaldonado.me"},
+			)
+			Expect(testClient.Create(ctx, node)).Should(Succeed())
+			Eventually(setNodeAddresses(ctx, nodeKey.Name,
+				corev1.NodeAddress{Type: corev1.NodeInternalIP, Address: "10.0.0.6"},
+				corev1.NodeAddress{Type: corev1.NodeExternalDNS, Address: "node-2.compute.example.com"},
+			), Timeout, Interval).Should(Succeed())
+
+			Eventually(func() bool {
+				return FakeDns.HasRecord(recordName, "198.51.100.7")
+			}, Timeout, Interval).Should(BeTrue())
+
+			By("Deleting Node")
+			deleteAndWait(ctx, nodeKey, &corev1.Node{})
+
+			Eventually(func() bool {
+				_, found := FakeDns.Record(recordName)
+				return found
 			}, Timeout, Interval).Should(BeFalse())
 		})
 	})
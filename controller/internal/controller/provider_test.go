@@ -0,0 +1,145 @@
+package controller_test
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	agonesv1 "agones.dev/agones/pkg/apis/agones/v1"
+	. "github.com/onsi/ginkgo"
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+var _ = Describe("DNS provider failures", func() {
+	const (
+		Timeout  = time.Second * 10
+		Interval = time.Millisecond * 250
+	)
+
+	var (
+		GameServerNodeName string            = "mc-node"
+		DomainAnnotations  map[string]string = map[string]string{"agones-mc/domain": "saulmaldonado.me"}
+		ctx                context.Context   = context.Background()
+	)
+
+	Context("When the provider returns a transient error", func() {
+		It("Should retry until the DNS record is created", func() {
+			gameServerKey := types.NamespacedName{Namespace: metav1.NamespaceDefault, Name: "mc-server-transient"}
+			recordName := "_minecraft._tcp.mc-server-transient.saulmaldonado.me."
+
+			FakeDns.InjectFault(recordName, errors.New("connection reset by peer"))
+			defer FakeDns.ClearFault(recordName)
+
+			By("creating a new GameServer")
+			Expect(testClient.Create(ctx, newTestGameServer(gameServerKey.Name, GameServerNodeName, 7100, DomainAnnotations, nil))).Should(Succeed())
+
+			By("Checking that no annotation is set while the provider fails")
+			Consistently(getAnnotation(ctx, gameServerKey, &agonesv1.GameServer{}, "agones-mc/externalDNS"), time.Second, Interval).Should(BeEmpty())
+
+			By("Recovering the provider")
+			FakeDns.ClearFault(recordName)
+
+			Eventually(func() bool {
+				return FakeDns.HasRecord(recordName, "0 0 7100 mc-node.saulmaldonado.me.")
+			}, Timeout, Interval).Should(BeTrue())
+
+			Eventually(getAnnotation(ctx, gameServerKey, &agonesv1.GameServer{}, "agones-mc/externalDNS"), Timeout, Interval).Should(Equal("mc-server-transient.saulmaldonado.me."))
+
+			deleteAndWait(ctx, gameServerKey, &agonesv1.GameServer{})
+		})
+	})
+
+	Context("When the provider returns a client error", func() {
+		It("Should not retry until the GameServer is updated", func() {
+			gameServerKey := types.NamespacedName{Namespace: metav1.NamespaceDefault, Name: "mc-server-forbidden"}
+			recordName := "_minecraft._tcp.mc-server-forbidden.saulmaldonado.me."
+
+			FakeDns.InjectFault(recordName, &FakeProviderError{Code: 403, Reason: "forbidden"})
+			defer FakeDns.ClearFault(recordName)
+
+			By("creating a new GameServer")
+			Expect(testClient.Create(ctx, newTestGameServer(gameServerKey.Name, GameServerNodeName, 7101, DomainAnnotations, nil))).Should(Succeed())
+
+			Consistently(getAnnotation(ctx, gameServerKey, &agonesv1.GameServer{}, "agones-mc/externalDNS"), time.Second, Interval).Should(BeEmpty())
+
+			By("Recovering the provider")
+			FakeDns.ClearFault(recordName)
+
+			By("Checking that the request is not requeued")
+			Consistently(func() bool {
+				_, found := FakeDns.Record(recordName)
+				return found
+			}, time.Second*2, Interval).Should(BeFalse())
+
+			By("Updating the GameServer")
+			Eventually(func() error {
+				gs := &agonesv1.GameServer{}
+				if err := testClient.Get(ctx, gameServerKey, gs); err != nil {
+					return err
+				}
+				gs.Labels = map[string]string{"retry": "true"}
+				return testClient.Update(ctx, gs)
+			}, Timeout, Interval).Should(Succeed())
+
+			Eventually(func() bool {
+				return FakeDns.HasRecord(recordName, "0 0 7101 mc-node.saulmaldonado.me.")
+			}, Timeout, Interval).Should(BeTrue())
+
+			deleteAndWait(ctx, gameServerKey, &agonesv1.GameServer{})
+		})
+	})
+
+	// creating a record that already exists fails with a client error, which is not retried
+	Context("When the DNS record already exists", func() {
+		It("Should not adopt the existing record", func() {
+			gameServerKey := types.NamespacedName{Namespace: metav1.NamespaceDefault, Name: "mc-server-exists"}
+			recordName := "_minecraft._tcp.mc-server-exists.saulmaldonado.me."
+			recordData := "0 0 7102 mc-node.saulmaldonado.me."
+
+			By("Adding a record left behind by a previous GameServer")
+			FakeDns.SetRecord(recordName, recordData)
+
+			By("creating a new GameServer with the same name")
+			Expect(testClient.Create(ctx, newTestGameServer(gameServerKey.Name, GameServerNodeName, 7102, DomainAnnotations, nil))).Should(Succeed())
+
+			By("Checking that no annotation is set")
+			Consistently(getAnnotation(ctx, gameServerKey, &agonesv1.GameServer{}, "agones-mc/externalDNS"), time.Second*2, Interval).Should(BeEmpty())
+			Expect(FakeDns.HasRecord(recordName, recordData)).Should(BeTrue())
+
+			By("Deleting GameServer")
+			deleteAndWait(ctx, gameServerKey, &agonesv1.GameServer{})
+
+			By("Checking that the existing record is left behind")
+			Consistently(func() bool {
+				return FakeDns.HasRecord(recordName, recordData)
+			}, time.Second*2, Interval).Should(BeTrue())
+		})
+	})
+
+	Context("When the provider is slow", func() {
+		It("Should create and remove the DNS record", func() {
+			gameServerKey := types.NamespacedName{Namespace: metav1.NamespaceDefault, Name: "mc-server-slow"}
+			recordName := "_minecraft._tcp.mc-server-slow.saulmaldonado.me."
+
+			FakeDns.SetLatency(time.Millisecond * 500)
+			defer FakeDns.SetLatency(0)
+
+			By("creating a new GameServer")
+			Expect(testClient.Create(ctx, newTestGameServer(gameServerKey.Name, GameServerNodeName, 7103, DomainAnnotations, nil))).Should(Succeed())
+
+			Eventually(func() bool {
+				return FakeDns.HasRecord(recordName, "0 0 7103 mc-node.saulmaldonado.me.")
+			}, Timeout, Interval).Should(BeTrue())
+
+			By("Deleting GameServer")
+			deleteAndWait(ctx, gameServerKey, &agonesv1.GameServer{})
+
+			Eventually(func() bool {
+				_, found := FakeDns.Record(recordName)
+				return found
+			}, Timeout, Interval).Should(BeFalse())
+		})
+	})
+})
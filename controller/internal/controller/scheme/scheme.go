@@ -2,6 +2,8 @@ package scheme
 
 import (
 	"fmt"
+	"net"
+	"strings"
 
 	agonesv1 "agones.dev/agones/pkg/apis/agones/v1"
 	corev1 "k8s.io/api/core/v1"
@@ -44,6 +46,37 @@ func GetNodeExternalDNS(node *corev1.Node) (string, bool) {
 	return "", false
 }
 
+// Node annotation with the IP of a load balancer in front of the Node.
+// Used for Nodes that are only reachable through NAT or a cloud load balancer
+const LoadBalancerIPAnnotation string = "agones-mc/loadBalancerIP"
+
+// Returns the address Node records should point to and whether it is a hostname.
+// The Node's external IP is preferred, falling back to the load balancer IP annotation and then the Node's external DNS name
+func GetNodeExternalTarget(node *corev1.Node) (string, bool, error) {
+	if externalIp, err := GetNodeExternalAddress(node); err == nil {
+		return externalIp, false, nil
+	}
+
+	if lbIp, found := GetNodeLoadBalancerIP(node); found {
+		return lbIp, false, nil
+	}
+
+	if externalDns, found := GetNodeExternalDNS(node); found {
+		return externalDns, true, nil
+	}
+
+	return "", false, &NoNodeExternalTarget{node.Name}
+}
+
+func GetNodeLoadBalancerIP(node *corev1.Node) (string, bool) {
+	lbIp, found := node.GetAnnotations()[LoadBalancerIPAnnotation]
+	if !found || net.ParseIP(strings.TrimSpace(lbIp)) == nil {
+		return "", false
+	}
+
+	return strings.TrimSpace(lbIp), true
+}
+
 type NoNodeExternalIP struct {
 	NodeName string
 }
@@ -52,11 +85,23 @@ func (e *NoNodeExternalIP) Error() string {
 	return fmt.Sprintf("%s has no external IP", e.NodeName)
 }
 
+type NoNodeExternalTarget struct {
+	NodeName string
+}
+
+func (e *NoNodeExternalTarget) Error() string {
+	return fmt.Sprintf("%s has no external IP, load balancer IP or external DNS name", e.NodeName)
+}
+
 func IsBeforePodCreated(gs *agonesv1.GameServer) bool {
 	state := gs.Status.State
 	return state == agonesv1.GameServerStatePortAllocation || state == agonesv1.GameServerStateCreating || state == agonesv1.GameServerStateStarting
 }
 
+func IsAllocated(gs *agonesv1.GameServer) bool {
+	return gs.Status.State == agonesv1.GameServerStateAllocated
+}
+
 func IsResourceDeleted(obj client.Object) bool {
 	return !obj.GetDeletionTimestamp().IsZero()
 }
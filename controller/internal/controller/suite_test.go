@@ -1,8 +1,13 @@
 package controller_test
 
 import (
+	"context"
+	"errors"
+	"fmt"
 	"path/filepath"
+	"sync"
 	"testing"
+	"time"
 
 	agonesv1 "agones.dev/agones/pkg/apis/agones/v1"
 	. "github.com/onsi/ginkgo"
@@ -11,6 +16,8 @@ import (
 	schm "github.com/saulmaldonado/agones-minecraft/controller/internal/controller/scheme"
 	"github.com/saulmaldonado/agones-minecraft/controller/internal/dns"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -23,7 +30,7 @@ import (
 var (
 	testClient      client.Client
 	testEnv         *envtest.Environment
-	FakeDns         *TestDnsClient
+	FakeDns         *FakeDnsClient
 	DefaultPriority = 0
 	DefaultWeight   = 0
 )
@@ -60,7 +67,7 @@ var _ = BeforeSuite(func() {
 
 	Expect(err).NotTo(HaveOccurred())
 
-	FakeDns = &TestDnsClient{DnsRecords: []string{}}
+	FakeDns = NewFakeDnsClient()
 
 	err = ctrl.NewControllerManagedBy(manager).For(&agonesv1.GameServer{}).WithEventFilter(
 		predicate.NewPredicateFuncs(func(object client.Object) bool {
@@ -99,68 +106,279 @@ var _ = BeforeSuite(func() {
 	}()
 }, 60)
 
-type TestDnsClient struct {
-	DnsRecords []string
+// Provider API error returned by FakeDnsClient. Like googleapi errors, these are client errors
+type FakeProviderError struct {
+	Code   int
+	Reason string
 }
 
-func (d *TestDnsClient) SetGameServerExternalDns(hostname string, gs *agonesv1.GameServer) error {
-	nodeARecord := dns.JoinARecordName(hostname, gs.Status.NodeName)
-	srvRecord := dns.JoinSrvRecordName(hostname, gs.Name)
+func (e *FakeProviderError) Error() string {
+	return fmt.Sprintf("fake provider error %d: %s", e.Code, e.Reason)
+}
 
-	port := gs.Status.Ports[0].Port
+var (
+	ErrFakeAlreadyExists = &FakeProviderError{Code: 409, Reason: "alreadyExists"}
+	ErrFakeNotFound      = &FakeProviderError{Code: 404, Reason: "notFound"}
+)
 
-	resourceRecord := dns.JoinSrvRR(srvRecord, uint16(port), DefaultPriority, DefaultWeight, nodeARecord)
+// In-memory DNS provider with injectable faults and latency.
+// Records are stored by name with their record data, e.g. "_minecraft._tcp.mc-server.saulmaldonado.me." -> "0 0 7000 mc-node.saulmaldonado.me."
+type FakeDnsClient struct {
+	mu      sync.Mutex
+	records map[string]string
+	faults  map[string]error
+	latency time.Duration
+}
 
-	d.DnsRecords = append(d.DnsRecords, srvRecord+" "+resourceRecord)
+func NewFakeDnsClient() *FakeDnsClient {
+	return &FakeDnsClient{records: map[string]string{}, faults: map[string]error{}}
+}
 
-	return nil
+func (d *FakeDnsClient) SetGameServerExternalDns(hostname string, gs *agonesv1.GameServer) error {
+	return d.add(dns.JoinSrvRecordName(hostname, gs.Name), srvRecordData(hostname, gs))
 }
 
-func (d *TestDnsClient) RemoveGameServerExternalDns(hostname string, gs *agonesv1.GameServer) error {
-	nodeARecord := dns.JoinARecordName(hostname, gs.Status.NodeName)
-	srvRecord := dns.JoinSrvRecordName(hostname, gs.Name)
+func (d *FakeDnsClient) RemoveGameServerExternalDns(hostname string, gs *agonesv1.GameServer) error {
+	return d.remove(dns.JoinSrvRecordName(hostname, gs.Name), srvRecordData(hostname, gs))
+}
 
-	port := gs.Status.Ports[0].Port
+func (d *FakeDnsClient) SetGameServerAliasDns(hostname string, alias string, gs *agonesv1.GameServer) error {
+	return d.add(dns.JoinSrvRecordName(hostname, alias), srvRecordData(hostname, gs))
+}
+
+func (d *FakeDnsClient) RemoveGameServerAliasDns(hostname string, alias string, gs *agonesv1.GameServer) error {
+	return d.remove(dns.JoinSrvRecordName(hostname, alias), srvRecordData(hostname, gs))
+}
 
-	resourceRecord := dns.JoinSrvRR(srvRecord, uint16(port), DefaultPriority, DefaultWeight, nodeARecord)
+func (d *FakeDnsClient) SetNodeExternalDns(hostname string, node *corev1.Node) error {
+	target, err := nodeRecordData(node)
+	if err != nil {
+		return err
+	}
 
-	recordToDelete := srvRecord + " " + resourceRecord
+	return d.add(dns.JoinARecordName(hostname, node.Name), target)
+}
 
-	for i, record := range d.DnsRecords {
-		if record == recordToDelete {
-			d.DnsRecords = append(d.DnsRecords[:i], d.DnsRecords[i+1:]...)
-		}
+func (d *FakeDnsClient) RemoveNodeExternalDns(hostname string, node *corev1.Node) error {
+	target, err := nodeRecordData(node)
+	if err != nil {
+		return err
 	}
 
-	return nil
+	return d.remove(dns.JoinARecordName(hostname, node.Name), target)
 }
 
-func (d *TestDnsClient) SetNodeExternalDns(hostname string, node *corev1.Node) error {
-	aRecord := dns.JoinARecordName(hostname, node.Name)
+func (*FakeDnsClient) IgnoreClientError(err error) error {
+	var providerErr *FakeProviderError
+	if errors.As(err, &providerErr) {
+		return nil
+	}
+	return err
+}
 
-	d.DnsRecords = append(d.DnsRecords, aRecord)
+// Returns the record data for the record name
+func (d *FakeDnsClient) Record(name string) (string, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
 
-	return nil
+	data, ok := d.records[name]
+	return data, ok
 }
 
-func (d *TestDnsClient) RemoveNodeExternalDns(hostname string, node *corev1.Node) error {
-	aRecord := dns.JoinARecordName(hostname, node.Name)
+// Checks if a record with the name and data exists
+func (d *FakeDnsClient) HasRecord(name, data string) bool {
+	found, ok := d.Record(name)
+	return ok && found == data
+}
 
-	for i, record := range d.DnsRecords {
-		if aRecord == record {
-			d.DnsRecords = append(d.DnsRecords[:i], d.DnsRecords[i+1:]...)
-		}
+// Adds a record to the store without going through the provider methods
+func (d *FakeDnsClient) SetRecord(name, data string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.records[name] = data
+}
+
+// Fails every provider call for the record name with err until cleared
+func (d *FakeDnsClient) InjectFault(name string, err error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.faults[name] = err
+}
+
+func (d *FakeDnsClient) ClearFault(name string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	delete(d.faults, name)
+}
+
+// Delays every provider call by latency
+func (d *FakeDnsClient) SetLatency(latency time.Duration) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.latency = latency
+}
+
+func (d *FakeDnsClient) add(name, data string) error {
+	if err := d.call(name); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if _, ok := d.records[name]; ok {
+		return ErrFakeAlreadyExists
 	}
 
+	d.records[name] = data
 	return nil
 }
 
-func (*TestDnsClient) IgnoreClientError(err error) error {
+func (d *FakeDnsClient) remove(name, data string) error {
+	if err := d.call(name); err != nil {
+		return err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	// like Cloud DNS, deletions have to match the existing record
+	if found, ok := d.records[name]; !ok || found != data {
+		return ErrFakeNotFound
+	}
+
+	delete(d.records, name)
 	return nil
 }
 
-func (*TestDnsClient) IgnoreAlreadyExists(err error) error {
-	return nil
+func (d *FakeDnsClient) call(name string) error {
+	d.mu.Lock()
+	latency := d.latency
+	fault := d.faults[name]
+	d.mu.Unlock()
+
+	time.Sleep(latency)
+
+	return fault
+}
+
+func srvRecordData(hostname string, gs *agonesv1.GameServer) string {
+	nodeARecord := dns.JoinARecordName(hostname, gs.Status.NodeName)
+	port := gs.Status.Ports[0].Port
+
+	return dns.JoinSrvRR("", uint16(port), DefaultPriority, DefaultWeight, nodeARecord)
+}
+
+// Returns the A record IP or CNAME record hostname for the Node
+func nodeRecordData(node *corev1.Node) (string, error) {
+	target, isHostname, err := schm.GetNodeExternalTarget(node)
+	if err != nil {
+		return "", err
+	}
+
+	if isHostname {
+		return dns.EnsureTrailingDot(target), nil
+	}
+
+	return target, nil
+}
+
+// Returns a Scheduled GameServer hosted on nodeName with an allocated port
+func newTestGameServer(name, nodeName string, port int32, annotations, labels map[string]string) *agonesv1.GameServer {
+	container := "mc-server"
+
+	return &agonesv1.GameServer{
+		Status: agonesv1.GameServerStatus{
+			State:    agonesv1.GameServerStateScheduled,
+			NodeName: nodeName,
+			Ports: []agonesv1.GameServerStatusPort{
+				{Name: "mc", Port: port},
+			},
+		},
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "GameServer",
+			APIVersion: agonesv1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: annotations,
+			Labels:      labels,
+			Name:        name,
+			Namespace:   metav1.NamespaceDefault,
+		},
+		Spec: agonesv1.GameServerSpec{
+			Container: container,
+			Ports: []agonesv1.GameServerPort{
+				{
+					Name:          "mc",
+					PortPolicy:    agonesv1.Dynamic,
+					Container:     &container,
+					ContainerPort: 25565,
+					Protocol:      corev1.ProtocolTCP,
+				},
+			},
+			Template: corev1.PodTemplateSpec{
+				Spec: corev1.PodSpec{
+					Containers: []corev1.Container{
+						{
+							Name:  container,
+							Image: "itzg/minecraft-server",
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// Returns a Node without addresses. Node addresses can only be set through the status subresource
+func newTestNode(name string, annotations, labels map[string]string) *corev1.Node {
+	return &corev1.Node{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "Node",
+			APIVersion: corev1.SchemeGroupVersion.String(),
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Annotations: annotations,
+			Labels:      labels,
+			Name:        name,
+		},
+	}
+}
+
+// Sets the Node's addresses through the status subresource
+func setNodeAddresses(ctx context.Context, name string, addresses ...corev1.NodeAddress) func() error {
+	return func() error {
+		node := &corev1.Node{}
+		if err := testClient.Get(ctx, types.NamespacedName{Name: name}, node); err != nil {
+			return err
+		}
+		node.Status.Addresses = addresses
+		return testClient.Status().Update(ctx, node)
+	}
+}
+
+// Returns an annotation of the resource or an empty string if the resource is not found
+func getAnnotation(ctx context.Context, key types.NamespacedName, obj client.Object, annotation string) func() string {
+	return func() string {
+		if err := testClient.Get(ctx, key, obj); err != nil {
+			return ""
+		}
+		return obj.GetAnnotations()[annotation]
+	}
+}
+
+// Deletes the resource and waits for its finalizers to be removed
+func deleteAndWait(ctx context.Context, key types.NamespacedName, obj client.Object) {
+	Expect(testClient.Get(ctx, key, obj)).Should(Succeed())
+	Expect(testClient.Delete(ctx, obj)).Should(Succeed())
+
+	Eventually(func() error {
+		return testClient.Get(ctx, key, obj)
+	}, time.Second*10, time.Millisecond*250).ShouldNot(Succeed())
 }
 
 var _ = AfterSuite(func() {
@@ -26,6 +26,7 @@ const (
 	DefaultWeight   int    = 0
 	SRV             string = "SRV"
 	A               string = "A"
+	CNAME           string = "CNAME"
 	AlreadyExists   string = "alreadyExists"
 )
 
@@ -53,17 +54,39 @@ func (c *GoogleDnsClient) RemoveGameServerExternalDns(hostname string, gs *agone
 	return err
 }
 
+func (c *GoogleDnsClient) SetGameServerAliasDns(hostname string, alias string, gs *agonesv1.GameServer) error {
+	change := dns.Change{}
+
+	nodeARecord := mcDns.JoinARecordName(hostname, gs.Status.NodeName)
+	srvRecord := NewAliasSrvRecordSet(hostname, alias, gs, DefaultTtl, nodeARecord)
+
+	change.Additions = []*dns.ResourceRecordSet{srvRecord}
+	_, err := c.Changes.Create(c.config.GoogleProjectId, c.config.GoogleManagedZone, &change).Do()
+
+	return err
+}
+
+func (c *GoogleDnsClient) RemoveGameServerAliasDns(hostname string, alias string, gs *agonesv1.GameServer) error {
+	change := dns.Change{}
+
+	nodeARecord := mcDns.JoinARecordName(hostname, gs.Status.NodeName)
+	srvRecord := NewAliasSrvRecordSet(hostname, alias, gs, DefaultTtl, nodeARecord)
+
+	change.Deletions = []*dns.ResourceRecordSet{srvRecord}
+	_, err := c.Changes.Create(c.config.GoogleProjectId, c.config.GoogleManagedZone, &change).Do()
+
+	return err
+}
+
 func (c *GoogleDnsClient) SetNodeExternalDns(hostname string, node *corev1.Node) error {
 	change := dns.Change{}
 
-	externalIp, err := scheme.GetNodeExternalAddress(node)
+	record, err := NewNodeRecordSet(hostname, node, DefaultTtl)
 	if err != nil {
 		return err
 	}
 
-	aRecord := NewARecordSet(hostname, externalIp, node.Name, DefaultTtl)
-
-	change.Additions = []*dns.ResourceRecordSet{aRecord}
+	change.Additions = []*dns.ResourceRecordSet{record}
 
 	_, err = c.Changes.Create(c.config.GoogleProjectId, c.config.GoogleManagedZone, &change).Do()
 
@@ -73,14 +96,12 @@ func (c *GoogleDnsClient) SetNodeExternalDns(hostname string, node *corev1.Node)
 func (c *GoogleDnsClient) RemoveNodeExternalDns(hostname string, node *corev1.Node) error {
 	change := dns.Change{}
 
-	externalIp, err := scheme.GetNodeExternalAddress(node)
+	record, err := NewNodeRecordSet(hostname, node, DefaultTtl)
 	if err != nil {
 		return err
 	}
 
-	aRecord := NewARecordSet(hostname, externalIp, node.Name, DefaultTtl)
-
-	change.Deletions = []*dns.ResourceRecordSet{aRecord}
+	change.Deletions = []*dns.ResourceRecordSet{record}
 
 	_, err = c.Changes.Create(c.config.GoogleProjectId, c.config.GoogleManagedZone, &change).Do()
 
@@ -88,9 +109,15 @@ func (c *GoogleDnsClient) RemoveNodeExternalDns(hostname string, node *corev1.No
 }
 
 func NewSrvRecordSet(hostname string, gs *agonesv1.GameServer, ttl int64, aRecordName string) *dns.ResourceRecordSet {
-	port := gs.Status.Ports[0].Port
+	return newSrvRecordSet(mcDns.JoinSrvRecordName(hostname, gs.Name), gs, ttl, aRecordName)
+}
 
-	srvRecordName := mcDns.JoinSrvRecordName(hostname, gs.Name)
+func NewAliasSrvRecordSet(hostname string, alias string, gs *agonesv1.GameServer, ttl int64, aRecordName string) *dns.ResourceRecordSet {
+	return newSrvRecordSet(mcDns.JoinSrvRecordName(hostname, alias), gs, ttl, aRecordName)
+}
+
+func newSrvRecordSet(srvRecordName string, gs *agonesv1.GameServer, ttl int64, aRecordName string) *dns.ResourceRecordSet {
+	port := gs.Status.Ports[0].Port
 
 	resourceRecord := mcDns.JoinSrvRR(aRecordName, uint16(port), DefaultPriority, DefaultWeight, aRecordName)
 
@@ -102,6 +129,25 @@ func NewARecordSet(hostname string, hostExternalIp string, resourceName string,
 	return &dns.ResourceRecordSet{Type: A, Name: recordName, Rrdatas: []string{hostExternalIp}, Ttl: ttl}
 }
 
+// Returns an A record for Nodes with an external or load balancer IP and a CNAME record for Nodes with only an external DNS name
+func NewNodeRecordSet(hostname string, node *corev1.Node, ttl int64) (*dns.ResourceRecordSet, error) {
+	target, isHostname, err := scheme.GetNodeExternalTarget(node)
+	if err != nil {
+		return nil, err
+	}
+
+	if isHostname {
+		return NewCNAMERecordSet(hostname, target, node.Name, ttl), nil
+	}
+
+	return NewARecordSet(hostname, target, node.Name, ttl), nil
+}
+
+func NewCNAMERecordSet(hostname string, canonicalName string, resourceName string, ttl int64) *dns.ResourceRecordSet {
+	recordName := mcDns.JoinARecordName(hostname, resourceName)
+	return &dns.ResourceRecordSet{Type: CNAME, Name: recordName, Rrdatas: []string{mcDns.EnsureTrailingDot(canonicalName)}, Ttl: ttl}
+}
+
 func (c *GoogleDnsClient) IgnoreClientError(err error) error {
 	if _, ok := err.(*googleapi.Error); ok {
 		return nil
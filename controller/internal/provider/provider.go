@@ -20,8 +20,9 @@ type ServerResponse struct {
 type DnsClient interface {
 	SetGameServerExternalDns(hostname string, gs *agonesv1.GameServer) error
 	RemoveGameServerExternalDns(hostname string, gs *agonesv1.GameServer) error
+	SetGameServerAliasDns(hostname string, alias string, gs *agonesv1.GameServer) error
+	RemoveGameServerAliasDns(hostname string, alias string, gs *agonesv1.GameServer) error
 	SetNodeExternalDns(hostname string, node *corev1.Node) error
 	RemoveNodeExternalDns(hostname string, node *corev1.Node) error
 	IgnoreClientError(err error) error
-	IgnoreAlreadyExists(err error) error
 }
@@ -0,0 +1,66 @@
+package provider
+
+import (
+	"context"
+
+	agonesv1 "agones.dev/agones/pkg/apis/agones/v1"
+	"golang.org/x/time/rate"
+	corev1 "k8s.io/api/core/v1"
+)
+
+// DnsClient wrapper that takes a token from a shared token bucket before every provider call.
+// Sharing one client across reconcilers keeps the total call rate under the provider's quota
+type RateLimitedDnsClient struct {
+	DnsClient
+	limiter *rate.Limiter
+}
+
+func NewRateLimitedDnsClient(client DnsClient, limit rate.Limit, burst int) *RateLimitedDnsClient {
+	return &RateLimitedDnsClient{DnsClient: client, limiter: rate.NewLimiter(limit, burst)}
+}
+
+func (c *RateLimitedDnsClient) SetGameServerExternalDns(hostname string, gs *agonesv1.GameServer) error {
+	if err := c.wait(); err != nil {
+		return err
+	}
+	return c.DnsClient.SetGameServerExternalDns(hostname, gs)
+}
+
+func (c *RateLimitedDnsClient) RemoveGameServerExternalDns(hostname string, gs *agonesv1.GameServer) error {
+	if err := c.wait(); err != nil {
+		return err
+	}
+	return c.DnsClient.RemoveGameServerExternalDns(hostname, gs)
+}
+
+func (c *RateLimitedDnsClient) SetGameServerAliasDns(hostname string, alias string, gs *agonesv1.GameServer) error {
+	if err := c.wait(); err != nil {
+		return err
+	}
+	return c.DnsClient.SetGameServerAliasDns(hostname, alias, gs)
+}
+
+func (c *RateLimitedDnsClient) RemoveGameServerAliasDns(hostname string, alias string, gs *agonesv1.GameServer) error {
+	if err := c.wait(); err != nil {
+		return err
+	}
+	return c.DnsClient.RemoveGameServerAliasDns(hostname, alias, gs)
+}
+
+func (c *RateLimitedDnsClient) SetNodeExternalDns(hostname string, node *corev1.Node) error {
+	if err := c.wait(); err != nil {
+		return err
+	}
+	return c.DnsClient.SetNodeExternalDns(hostname, node)
+}
+
+func (c *RateLimitedDnsClient) RemoveNodeExternalDns(hostname string, node *corev1.Node) error {
+	if err := c.wait(); err != nil {
+		return err
+	}
+	return c.DnsClient.RemoveNodeExternalDns(hostname, node)
+}
+
+func (c *RateLimitedDnsClient) wait() error {
+	return c.limiter.Wait(context.Background())
+}
@@ -1,19 +1,23 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"os"
 
 	agonesv1 "agones.dev/agones/pkg/apis/agones/v1"
 	ctrl "github.com/saulmaldonado/agones-minecraft/controller/internal/controller"
 	schm "github.com/saulmaldonado/agones-minecraft/controller/internal/controller/scheme"
+	"github.com/saulmaldonado/agones-minecraft/controller/internal/provider"
 	"github.com/saulmaldonado/agones-minecraft/controller/internal/provider/google"
+	"golang.org/x/time/rate"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	_ "k8s.io/client-go/plugin/pkg/client/auth/gcp"
 	controller "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/config"
+	ctrlOpts "sigs.k8s.io/controller-runtime/pkg/controller"
 	"sigs.k8s.io/controller-runtime/pkg/log/zap"
 	"sigs.k8s.io/controller-runtime/pkg/predicate"
 )
@@ -22,18 +26,38 @@ var (
 	ManagedZone  string
 	ProjectId    string
 	NodeHostname string
+	LogOptions   zap.Options
+
+	GameServerConcurrency int
+	NodeConcurrency       int
+	DnsRateLimit          float64
+	DnsBurst              int
 )
 
 func init() {
 	flag.StringVar(&ManagedZone, "zone", "", "DNS zone that the controller will manage")
 	flag.StringVar(&ProjectId, "gcp-project", "", "GCP project id")
+	flag.IntVar(&GameServerConcurrency, "gameserver-concurrency", 1, "Maximum number of GameServers reconciled concurrently")
+	flag.IntVar(&NodeConcurrency, "node-concurrency", 1, "Maximum number of Nodes reconciled concurrently")
+	flag.Float64Var(&DnsRateLimit, "dns-rate-limit", 10, "DNS provider calls per second shared by all controllers. 0 disables rate limiting")
+	flag.IntVar(&DnsBurst, "dns-burst", 20, "Maximum burst of DNS provider calls above --dns-rate-limit")
+
+	// --zap-devel, --zap-encoder, --zap-log-level and --zap-stacktrace-level
+	LogOptions.BindFlags(flag.CommandLine)
 
 	flag.Parse()
 }
 
 func main() {
 	scheme := runtime.NewScheme()
-	log := zap.New()
+	log := zap.New(zap.UseFlagOptions(&LogOptions))
+	controller.SetLogger(log)
+
+	// a limiter with no burst rejects every call
+	if DnsRateLimit > 0 && DnsBurst < 1 {
+		log.Error(errors.New("--dns-burst must be at least 1 when --dns-rate-limit is set"), "Invalid flags")
+		os.Exit(1)
+	}
 
 	log.Info("Adding scheme")
 
@@ -44,13 +68,20 @@ func main() {
 
 	log.Info("Initializing DNS client")
 
-	dns, err := google.NewDnsClient(ManagedZone, ProjectId)
+	googleDns, err := google.NewDnsClient(ManagedZone, ProjectId)
 
 	if err != nil {
 		log.Error(err, "Error Initializing DNS client")
 		os.Exit(1)
 	}
 
+	// one client is shared by both controllers so the rate limit applies to their combined calls
+	var dns provider.DnsClient = googleDns
+
+	if DnsRateLimit > 0 {
+		dns = provider.NewRateLimitedDnsClient(googleDns, rate.Limit(DnsRateLimit), DnsBurst)
+	}
+
 	log.Info("Setting up manager")
 
 	manager, err := controller.NewManager(config.GetConfigOrDie(), controller.Options{
@@ -69,7 +100,8 @@ func main() {
 		gs := object.(*agonesv1.GameServer)
 		return !schm.IsBeforePodCreated(gs)
 	})).
-		Complete(ctrl.NewGameServerReconciler(manager.GetClient(), manager.GetScheme(), log, dns)); err != nil {
+		WithOptions(ctrlOpts.Options{MaxConcurrentReconciles: GameServerConcurrency}).
+		Complete(ctrl.NewGameServerReconciler(manager.GetClient(), manager.GetScheme(), log.WithName("GameServer"), dns)); err != nil {
 
 		log.Error(err, "Error setting up GameServer controller")
 		os.Exit(1)
@@ -79,7 +111,8 @@ func main() {
 
 	if err := controller.NewControllerManagedBy(manager).
 		For(&corev1.Node{}).
-		Complete(ctrl.NewNodeReconciler(manager.GetClient(), manager.GetScheme(), log, dns)); err != nil {
+		WithOptions(ctrlOpts.Options{MaxConcurrentReconciles: NodeConcurrency}).
+		Complete(ctrl.NewNodeReconciler(manager.GetClient(), manager.GetScheme(), log.WithName("Node"), dns)); err != nil {
 
 		log.Error(err, "Error setting up Node controller")
 		os.Exit(1)